@@ -19,57 +19,50 @@ func convertBool(value string) (bool, error) {
 // converted to a boolean. An error will be returned if the
 // environment variable is not found or the conversion to
 // boolean fails.
-func ToBool(varName string) (bool, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return false, err
-	}
-
-	return convertBool(value)
+func ToBool(varName string, opts ...Option) (bool, error) {
+	return convert(varName, opts, convertBool)
 }
 
 // ToBoolSlice returns the value of the requested environment variable
 // converted to a slice of bools. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of bools fails.
-func ToBoolSlice(varName string, separator string) ([]bool, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return []bool{}, err
-	}
-
-	boolStrings := strings.Split(value, separator)
-	var bools = []bool{}
-	for _, b := range boolStrings {
-		convertedBool, err := convertBool(b)
-		if err != nil {
-			return []bool{}, err
-		}
-		bools = append(bools, convertedBool)
-	}
-	return bools, nil
+func ToBoolSlice(varName string, separator string, opts ...Option) ([]bool, error) {
+	return convertSlice(varName, separator, opts, convertBool)
 }
 
 // ToBoolWithDefault returns the value of the requested environment
 // variable converted to a boolean. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to boolean fails.
-func ToBoolWithDefault(varName string, defaultValue bool) bool {
-	value, err := ToBool(varName)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToBoolWithDefault(varName string, defaultValue bool, opts ...Option) bool {
+	value, err := ToBool(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToBoolSliceWithDefault returns the value of the requested environment
 // variable converted to a slice of time.Bools. The default value
 // passed as the second parameter will be returned if the environment
 // variable is not found or the conversion to time.Bool fails.
-func ToBoolSliceWithDefault(varName string, separator string, defaultValue []bool) []bool {
-	value, err := ToBoolSlice(varName, separator)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToBoolSliceWithDefault(varName string, separator string, defaultValue []bool, opts ...Option) []bool {
+	value, err := ToBoolSlice(varName, separator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
+}
+
+// ToBoolSlice2D returns the value of the requested environment variable
+// converted to a nested slice of bools. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToBoolSlice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]bool, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, convertBool)
+}
+
+// ToBoolSlice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of bools. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToBoolSlice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]bool, opts ...Option) [][]bool {
+	value, err := ToBoolSlice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
@@ -123,3 +123,19 @@ func TestToBoolSliceWithDefault(t *testing.T) {
 	}
 	runSliceWithDefaultEmptyTest[bool](t, ",", def, envconv.ToBoolSliceWithDefault)
 }
+
+func TestToBoolSlice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]bool
+		errorExpected bool
+	}{
+		{"TEST_BOOL_SLICE_2D_FLAGS", "true,false;1", [][]bool{{true, false}, {true}}, false},
+		{"TEST_BOOL_SLICE_2D_NOTABOOL", "true;notabool", [][]bool{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToBoolSlice2D)
+	}
+}
@@ -0,0 +1,156 @@
+package envconv
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// EnvBuilder builds a curated environment for a child process from a
+// base Source, such as a Snapshot, rather than passing on the whole
+// of os.Environ. The variables of the base Source are filtered by
+// glob patterns and may have their prefixes rewritten, and then
+// explicitly set variables are added on top.
+//
+// EnvBuilder implements Setter, so typed values can be added with
+// the Set functions and the Into option, formatted exactly as the
+// To* functions parse them.
+type EnvBuilder struct {
+	base     Source
+	allow    []string
+	deny     []string
+	rewrites [][2]string
+	overlay  map[string]string
+}
+
+// NewEnvBuilder returns an EnvBuilder starting from the variables of
+// the passed Source. If base is nil, the built environment holds
+// only the variables that are set explicitly.
+func NewEnvBuilder(base Source) *EnvBuilder {
+	return &EnvBuilder{base: base, overlay: map[string]string{}}
+}
+
+// Allow adds glob patterns, in the syntax of path.Match, for the names
+// of variables to keep from the base Source. If no patterns are
+// allowed, every variable is kept unless it is denied.
+func (b *EnvBuilder) Allow(patterns ...string) error {
+	if err := checkPatterns(patterns); err != nil {
+		return err
+	}
+	b.allow = append(b.allow, patterns...)
+	return nil
+}
+
+// Deny adds glob patterns, in the syntax of path.Match, for the names
+// of variables to drop from the base Source. Deny takes precedence
+// over Allow.
+func (b *EnvBuilder) Deny(patterns ...string) error {
+	if err := checkPatterns(patterns); err != nil {
+		return err
+	}
+	b.deny = append(b.deny, patterns...)
+	return nil
+}
+
+// RewritePrefix renames every kept variable from the base Source
+// whose name starts with from, replacing that prefix with to. Only
+// the first matching rewrite is applied to each variable, and
+// a rewritten variable replaces any other of the same name.
+// If two variables are rewritten to the same name, the one
+// matched by the earlier call to RewritePrefix is kept.
+func (b *EnvBuilder) RewritePrefix(from string, to string) {
+	b.rewrites = append(b.rewrites, [2]string{from, to})
+}
+
+// Set sets the named variable in the built environment, replacing
+// any variable of the same name from the base Source. Variables
+// set in this way are not filtered or rewritten.
+func (b *EnvBuilder) Set(name string, value string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	b.overlay[name] = value
+	return nil
+}
+
+// Env returns the built environment.
+func (b *EnvBuilder) Env() *Env {
+	values := map[string]string{}
+	rewritten := map[string]string{}
+	if b.base != nil {
+		base := environMap(b.base)
+		// rule holds the index of the rewrite that produced each
+		// rewritten name, so that the earliest rewrite wins.
+		rule := map[string]int{}
+		for _, name := range sortedNames(base) {
+			if !b.keep(name) {
+				continue
+			}
+			to, i := b.rewrite(name)
+			if i < 0 {
+				values[name] = base[name]
+				continue
+			}
+			if j, seen := rule[to]; !seen || i < j {
+				rewritten[to], rule[to] = base[name], i
+			}
+		}
+	}
+	for name, value := range rewritten {
+		values[name] = value
+	}
+	for name, value := range b.overlay {
+		values[name] = value
+	}
+	return &Env{values: values}
+}
+
+// Environ returns the built environment in the form "key=value", in
+// lexicographical order of name, ready to be used as the Env of an
+// exec.Cmd.
+func (b *EnvBuilder) Environ() []string {
+	return b.Env().Environ()
+}
+
+// keep reports whether the named variable from the base Source
+// passes the allow and deny patterns.
+func (b *EnvBuilder) keep(name string) bool {
+	if matchAny(b.deny, name) {
+		return false
+	}
+	return len(b.allow) == 0 || matchAny(b.allow, name)
+}
+
+// rewrite returns the name of the passed variable after the first
+// matching prefix rewrite, and the index of that rewrite, or -1
+// if there was none.
+func (b *EnvBuilder) rewrite(name string) (string, int) {
+	for i, r := range b.rewrites {
+		if strings.HasPrefix(name, r[0]) {
+			return r[1] + strings.TrimPrefix(name, r[0]), i
+		}
+	}
+	return name, -1
+}
+
+// checkPatterns returns an error if any of the passed glob
+// patterns is malformed.
+func checkPatterns(patterns []string) error {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("envconv: invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether the passed name matches any of the
+// passed glob patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
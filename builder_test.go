@@ -0,0 +1,79 @@
+package envconv_test
+
+import (
+	"path"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvBuilder(t *testing.T) {
+	base := envconv.NewEnv(map[string]string{
+		"HOME":            "/home/app",
+		"PATH":            "/usr/bin",
+		"AWS_SECRET":      "hunter2",
+		"APP_PORT":        "8080",
+		"APP_DB_PASSWORD": "hunter2",
+		"APP_HOST":        "localhost",
+		"CHILD_HOST":      "example.com",
+		"LANG":            "C",
+	})
+
+	b := envconv.NewEnvBuilder(base)
+	assert.NoError(t, b.Allow("PATH", "HOME", "APP_*", "CHILD_*"))
+	assert.NoError(t, b.Deny("*PASSWORD*", "*SECRET*"))
+	b.RewritePrefix("APP_", "CHILD_")
+	assert.NoError(t, envconv.Set("CHILD_TIMEOUT", 1500*time.Millisecond, "", envconv.Into(b)))
+	assert.NoError(t, envconv.Set("CHILD_PEERS", []string{"a,b", "c"}, ",", envconv.Into(b)))
+	assert.NoError(t, b.Set("HOME", "/tmp"))
+
+	expected := []string{
+		"CHILD_HOST=localhost",
+		`CHILD_PEERS="a,b",c`,
+		"CHILD_PORT=8080",
+		"CHILD_TIMEOUT=1.5s",
+		"HOME=/tmp",
+		"PATH=/usr/bin",
+	}
+	assert.Equal(t, expected, b.Environ(), "they should be equal")
+	assert.Equal(t, expected, b.Environ(), "it should be deterministic")
+
+	timeout, err := envconv.ToDuration("CHILD_TIMEOUT", envconv.FromSource(b.Env()))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 1500*time.Millisecond, timeout, "they should be equal")
+
+	t.Run("without patterns", func(t *testing.T) {
+		assert.Equal(t, base.Environ(), envconv.NewEnvBuilder(base).Environ(), "they should be equal")
+	})
+
+	t.Run("rewrite collision", func(t *testing.T) {
+		base := envconv.NewEnv(map[string]string{"OLD_X": "1", "LEGACY_X": "2", "APP_X": "3"})
+		for i := 0; i < 50; i++ {
+			b := envconv.NewEnvBuilder(base)
+			b.RewritePrefix("OLD_", "APP_")
+			b.RewritePrefix("LEGACY_", "APP_")
+			assert.Equal(t, []string{"APP_X=1"}, b.Environ(), "the earlier rewrite should win")
+
+			b = envconv.NewEnvBuilder(base)
+			b.RewritePrefix("LEGACY_", "APP_")
+			b.RewritePrefix("OLD_", "APP_")
+			assert.Equal(t, []string{"APP_X=2"}, b.Environ(), "the earlier rewrite should win")
+		}
+	})
+
+	t.Run("without a base", func(t *testing.T) {
+		b := envconv.NewEnvBuilder(nil)
+		assert.NoError(t, b.Set("PORT", "80"))
+		assert.Equal(t, []string{"PORT=80"}, b.Environ(), "they should be equal")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		assert.ErrorIs(t, envconv.NewEnvBuilder(base).Allow("APP_["), path.ErrBadPattern)
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		assert.Error(t, envconv.NewEnvBuilder(base).Set("A=B", ""), "there should be an error")
+	})
+}
@@ -2,57 +2,51 @@ package envconv
 
 import "strconv"
 
-// ToByte returns the value of the requested environment variable
-// converted to a byte. An error will be returned if the
-// environment variable is not found or the conversion to
-// byte fails.
-func ToByte(varName string) (byte, error) {
-	value, err := loadFromEnvironment(varName, true)
+// convertByte converts the passed string to a byte. It will
+// also return an error, if applicable.
+func convertByte(value string) (byte, error) {
+	convertedValue, err := strconv.ParseUint(value, 10, 8)
 	if err != nil {
 		return byte(0), err
 	}
-
-	var convertedValue uint64
-	convertedValue, err = strconv.ParseUint(value, 10, 8)
-	if err != nil {
-		return byte(0), err
-	}
-
 	return byte(convertedValue), nil
 }
 
+// ToByte returns the value of the requested environment variable
+// converted to a byte. An error will be returned if the
+// environment variable is not found or the conversion to
+// byte fails.
+func ToByte(varName string, opts ...Option) (byte, error) {
+	return convert(varName, opts, convertByte)
+}
+
 // ToByteSlice returns the value of the requested environment variable
 // converted to a byte slice. An error will be returned if the
 // environment variable is not found.
-func ToByteSlice(varName string) ([]byte, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
+func ToByteSlice(varName string, opts ...Option) ([]byte, error) {
+	value, err := convert(varName, opts, func(value string) ([]byte, error) {
+		return []byte(value), nil
+	})
+	if err != nil || value == nil {
 		return []byte{}, err
 	}
-
-	return []byte(value), nil
+	return value, nil
 }
 
 // ToByteWithDefault returns the value of the requested environment
 // variable converted to a byte. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to byte fails.
-func ToByteWithDefault(varName string, defaultValue byte) byte {
-	value, err := ToByte(varName)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToByteWithDefault(varName string, defaultValue byte, opts ...Option) byte {
+	value, err := ToByte(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToByteSliceWithDefault returns the value of the requested environment
 // variable converted to a byte slice. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found.
-func ToByteSliceWithDefault(varName string, defaultValue []byte) []byte {
-	value, err := ToByteSlice(varName)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToByteSliceWithDefault(varName string, defaultValue []byte, opts ...Option) []byte {
+	value, err := ToByteSlice(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
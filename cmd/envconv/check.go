@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rmhubbert/envconv"
+)
+
+// problem is a single failed check of an environment variable.
+type problem struct {
+	Name    string
+	Type    string
+	Message string
+}
+
+// runCheck implements the check subcommand.
+func runCheck(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("check", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: envconv check -schema FILE [-env-file FILE] [-prefix PREFIX]")
+		flags.PrintDefaults()
+	}
+	schemaPath := flags.String("schema", "", "read the JSON or YAML schema from `file`")
+	envFile := flags.String("env-file", "", "check the variables assigned in the .env `file`, rather than the environment")
+	prefix := flags.String("prefix", "", "report variables starting with `prefix` that are not in the schema")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *schemaPath == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	s, err := loadSchema(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "envconv: %v\n", err)
+		return 2
+	}
+	src := envconv.Environment
+	if *envFile != "" {
+		if src, err = envconv.ReadFileSource(*envFile); err != nil {
+			fmt.Fprintf(stderr, "envconv: %v\n", err)
+			return 2
+		}
+	}
+
+	var problems []problem
+	for _, v := range s.Variables {
+		problems = append(problems, checkVariable(v, src, *prefix)...)
+	}
+	if *prefix != "" {
+		unknown, err := checkUnknown(s, src, *prefix)
+		if err != nil {
+			fmt.Fprintf(stderr, "envconv: %v\n", err)
+			return 2
+		}
+		problems = append(problems, unknown...)
+	}
+	if len(problems) == 0 {
+		fmt.Fprintf(stdout, "ok: %d variables checked\n", len(s.Variables))
+		return 0
+	}
+
+	w := tabwriter.NewWriter(stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tTYPE\tPROBLEM")
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Name, p.Type, p.Message)
+	}
+	w.Flush()
+	fmt.Fprintf(stderr, "envconv: %d problems found\n", len(problems))
+	return 1
+}
+
+// loadSchema reads the schema from the file at the passed path.
+func loadSchema(path string) (*schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return readSchema(f)
+}
+
+// checkUnknown returns a problem for each variable in src that starts
+// with prefix but is not listed in the passed schema.
+func checkUnknown(s *schema, src envconv.Source, prefix string) ([]problem, error) {
+	declared := envconv.NewVarSet("schema")
+	for _, v := range s.Variables {
+		declared.String(v.Name, "", "")
+	}
+
+	err := declared.CheckUnknown(src, prefix)
+	if err == nil {
+		return nil, nil
+	}
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	var problems []problem
+	for _, err := range errs {
+		var undeclared *envconv.UndeclaredError
+		if !errors.As(err, &undeclared) {
+			return nil, err
+		}
+		problems = append(problems, problem{
+			Name:    undeclared.VarName,
+			Type:    "-",
+			Message: "not in schema" + suggestions(undeclared.Suggestions()),
+		})
+	}
+	return problems, nil
+}
+
+// suggestions formats the passed similar names as a clause to add
+// to a problem, or returns an empty string if there are none.
+func suggestions(names []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	return " (did you mean " + strings.Join(names, " or ") + "?)"
+}
+
+// checkVariable returns the problems found with the passed variable,
+// as it is set in src. Similar names suggested for a missing variable
+// are restricted to those starting with prefix.
+func checkVariable(v variable, src envconv.Source, prefix string) []problem {
+	report := func(format string, args ...any) problem {
+		return problem{Name: v.Name, Type: v.typeName(), Message: fmt.Sprintf(format, args...)}
+	}
+
+	opts := []envconv.Option{envconv.FromSource(src), envconv.SuggestPrefix(prefix)}
+	if v.Secret {
+		opts = append(opts, envconv.Redact())
+	}
+
+	c := converters[v.Type]
+	values, err := convertVariable(v, c, opts)
+	var notSet *envconv.NotSetError
+	if errors.As(err, &notSet) {
+		if v.Required {
+			return []problem{report("required but not set%s", suggestions(notSet.Suggestions()))}
+		}
+		return nil
+	}
+	if err != nil {
+		return []problem{report("%v", err)}
+	}
+
+	var raw []any
+	if len(v.Enum) > 0 || v.pattern != nil {
+		raw, err = convertVariable(v, converters["string"], opts)
+		if err != nil {
+			return []problem{report("%v", err)}
+		}
+	}
+
+	var problems []problem
+	for i := range values {
+		element := ""
+		if len(values) > 1 || v.Separator != "" {
+			element = fmt.Sprintf("element %d ", i)
+		}
+		display := func(value any) string {
+			if v.Secret {
+				return ""
+			}
+			return strconv.Quote(fmt.Sprint(value)) + " "
+		}
+
+		if v.Min != nil || v.Max != nil {
+			measured := c.measure(values[i])
+			if v.Min != nil {
+				if lower, _ := c.measureBound(*v.Min); measured < lower {
+					problems = append(problems, report("%s%sis less than min %s", element, display(values[i]), *v.Min))
+				}
+			}
+			if v.Max != nil {
+				if upper, _ := c.measureBound(*v.Max); measured > upper {
+					problems = append(problems, report("%s%sis greater than max %s", element, display(values[i]), *v.Max))
+				}
+			}
+		}
+		if len(v.Enum) > 0 && !slices.Contains(v.Enum, raw[i].(string)) {
+			problems = append(problems, report("%s%sis not one of %q", element, display(raw[i]), v.Enum))
+		}
+		if v.pattern != nil && !v.pattern.MatchString(raw[i].(string)) {
+			problems = append(problems, report("%s%sdoes not match pattern %q", element, display(raw[i]), v.Pattern))
+		}
+	}
+	return problems
+}
+
+// convertVariable converts the passed variable using the passed
+// converter, as a single value or a slice as the schema requires.
+func convertVariable(v variable, c converter, opts []envconv.Option) ([]any, error) {
+	switch {
+	case v.InnerSeparator != "":
+		return c.slice2D(v.Name, v.Separator, v.InnerSeparator, opts...)
+	case v.Separator != "":
+		return c.slice(v.Name, v.Separator, opts...)
+	}
+	return c.scalar(v.Name, opts...)
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testSchema = `
+variables:
+  - name: TEST_CHECK_PORT
+    type: uint16
+    required: true
+    min: 1024
+  - name: TEST_CHECK_TIMEOUT
+    type: duration
+    max: 1m
+  - name: TEST_CHECK_SHARDS
+    type: int
+    separator: ";"
+    inner_separator: ","
+  - name: TEST_CHECK_LEVEL
+    type: string
+    enum: [debug, info, warn, error]
+  - name: TEST_CHECK_HOSTS
+    type: string
+    separator: ","
+    pattern: "^[a-z.]+$"
+  - name: TEST_CHECK_PASSWORD
+    type: string
+    required: true
+    secret: true
+    min: 8
+  - name: TEST_CHECK_FEATURES
+    type: json
+`
+
+// writeFile writes the passed contents to a file in a temporary
+// directory and returns its path.
+func writeFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// setCheckEnv sets the passed environment variables, unsetting every
+// other variable listed in testSchema.
+func setCheckEnv(env map[string]string) {
+	for _, name := range []string{"TEST_CHECK_PORT", "TEST_CHECK_TIMEOUT", "TEST_CHECK_SHARDS", "TEST_CHECK_LEVEL", "TEST_CHECK_HOSTS", "TEST_CHECK_PASSWORD", "TEST_CHECK_FEATURES"} {
+		os.Unsetenv(name)
+	}
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+}
+
+func TestCheckValid(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testSchema)
+	setCheckEnv(map[string]string{
+		"TEST_CHECK_PORT":     "8080",
+		"TEST_CHECK_TIMEOUT":  "30s",
+		"TEST_CHECK_SHARDS":   "1,2;3",
+		"TEST_CHECK_LEVEL":    "info",
+		"TEST_CHECK_HOSTS":    "a.example, b.example",
+		"TEST_CHECK_PASSWORD": "correct horse",
+		"TEST_CHECK_FEATURES": `{"beta":true}`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath}, &stdout, &stderr)
+	assert.Equal(t, 0, code, "they should be equal")
+	assert.Equal(t, "ok: 7 variables checked\n", stdout.String(), "they should be equal")
+	assert.Empty(t, stderr.String())
+}
+
+func TestCheckProblems(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testSchema)
+	setCheckEnv(map[string]string{
+		"TEST_CHECK_PORT":     "80",
+		"TEST_CHECK_TIMEOUT":  "2m",
+		"TEST_CHECK_SHARDS":   "1,2;x",
+		"TEST_CHECK_LEVEL":    "verbose",
+		"TEST_CHECK_HOSTS":    "a.example,B_HOST",
+		"TEST_CHECK_PASSWORD": "hunter2",
+		"TEST_CHECK_FEATURES": `{"beta":}`,
+	})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath}, &stdout, &stderr)
+	assert.Equal(t, 1, code, "they should be equal")
+	assert.Equal(t, "envconv: 7 problems found\n", stderr.String(), "they should be equal")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if assert.Len(t, lines, 8) {
+		assert.Regexp(t, `^VARIABLE\s+TYPE\s+PROBLEM$`, lines[0])
+		assert.Regexp(t, `^TEST_CHECK_PORT\s+uint16\s+"80" is less than min 1024$`, lines[1])
+		assert.Regexp(t, `^TEST_CHECK_TIMEOUT\s+duration\s+"2m0s" is greater than max 1m$`, lines[2])
+		assert.Regexp(t, `^TEST_CHECK_SHARDS\s+\[\]\[\]int\s+TEST_CHECK_SHARDS: element \[1\]\[0\] "x": `, lines[3])
+		assert.Regexp(t, `^TEST_CHECK_LEVEL\s+string\s+"verbose" is not one of \["debug" "info" "warn" "error"\]$`, lines[4])
+		assert.Regexp(t, `^TEST_CHECK_HOSTS\s+\[\]string\s+element 1 "B_HOST" does not match pattern`, lines[5])
+		assert.Regexp(t, `^TEST_CHECK_PASSWORD\s+string\s+is less than min 8$`, lines[6])
+		assert.Regexp(t, `^TEST_CHECK_FEATURES\s+json\s+TEST_CHECK_FEATURES: cannot convert .* invalid JSON at offset 9`, lines[7])
+	}
+	assert.NotContains(t, stdout.String(), "hunter2")
+}
+
+func TestCheckRequired(t *testing.T) {
+	schemaPath := writeFile(t, "schema.json", `{"variables": [
+		{"name": "TEST_CHECK_PORT", "type": "int", "required": true},
+		{"name": "TEST_CHECK_TIMEOUT", "type": "duration"}
+	]}`)
+	setCheckEnv(nil)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath}, &stdout, &stderr)
+	assert.Equal(t, 1, code, "they should be equal")
+	assert.Regexp(t, `TEST_CHECK_PORT\s+int\s+required but not set`, stdout.String())
+	assert.NotContains(t, stdout.String(), "TEST_CHECK_TIMEOUT")
+}
+
+func TestCheckEnvFile(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testSchema)
+	envPath := writeFile(t, ".env", `TEST_CHECK_PORT=8080
+TEST_CHECK_PASSWORD="correct horse"
+TEST_CHECK_LEVEL=loud
+`)
+	setCheckEnv(map[string]string{"TEST_CHECK_LEVEL": "info"})
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath, "-env-file", envPath}, &stdout, &stderr)
+	assert.Equal(t, 1, code, "they should be equal")
+	assert.Regexp(t, `TEST_CHECK_LEVEL\s+string\s+"loud" is not one of`, stdout.String())
+}
+
+func TestCheckEnvFileOnly(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testSchema)
+	envPath := writeFile(t, ".env", "TEST_CHECK_PORT=8080\n")
+	setCheckEnv(map[string]string{"TEST_CHECK_LEVEL": "info"})
+	os.Setenv("TEST_CHECK_PASWORD", "correct horse")
+	defer os.Unsetenv("TEST_CHECK_PASWORD")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath, "-env-file", envPath, "-prefix", "TEST_CHECK_"}, &stdout, &stderr)
+	assert.Equal(t, 1, code, "they should be equal")
+	assert.Regexp(t, `TEST_CHECK_PASSWORD\s+string\s+required but not set\n`, stdout.String(), "the environment should not be suggested")
+	assert.NotContains(t, stdout.String(), "not in schema", "the environment should not be checked")
+	assert.Equal(t, "info", os.Getenv("TEST_CHECK_LEVEL"), "the environment should not be changed")
+	assert.Equal(t, "", os.Getenv("TEST_CHECK_PORT"), "the environment should not be changed")
+}
+
+func TestCheckPrefix(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testSchema)
+	envPath := writeFile(t, ".env", `TEST_CHECK_PORT=8080
+TEST_CHECK_PASWORD="correct horse"
+TEST_CHECK_LEGACY=1
+`)
+	setCheckEnv(map[string]string{"TEST_CHECK_OTHER": "1"})
+	defer os.Unsetenv("TEST_CHECK_OTHER")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"check", "-schema", schemaPath, "-env-file", envPath, "-prefix", "TEST_CHECK_"}, &stdout, &stderr)
+	assert.Equal(t, 1, code, "they should be equal")
+	assert.Equal(t, "envconv: 3 problems found\n", stderr.String(), "they should be equal")
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if assert.Len(t, lines, 4) {
+		assert.Regexp(t, `^TEST_CHECK_PASSWORD\s+string\s+required but not set \(did you mean TEST_CHECK_PASWORD\?\)$`, lines[1])
+		assert.Regexp(t, `^TEST_CHECK_LEGACY\s+-\s+not in schema$`, lines[2])
+		assert.Regexp(t, `^TEST_CHECK_PASWORD\s+-\s+not in schema \(did you mean TEST_CHECK_PASSWORD\?\)$`, lines[3])
+	}
+}
+
+func TestCheckInvalidSchema(t *testing.T) {
+	testData := []struct {
+		name     string
+		schema   string
+		expected string
+	}{
+		{"unknown type", "variables:\n  - name: A\n    type: complex128\n", `A has unknown type "complex128"`},
+		{"unknown field", "variables:\n  - name: A\n    type: int\n    maximum: 5\n", "field maximum not found"},
+		{"no name", "variables:\n  - type: int\n", "variable 0 has no name"},
+		{"duplicate", "variables:\n  - name: A\n    type: int\n  - name: A\n    type: int\n", "A is listed more than once"},
+		{"byte slice", "variables:\n  - name: A\n    type: byte\n    separator: ','\n", `A type "byte" cannot be a slice`},
+		{"nested json", "variables:\n  - name: A\n    type: json\n    separator: ';'\n    inner_separator: ','\n", `A type "json" cannot be a nested slice`},
+		{"bool bound", "variables:\n  - name: A\n    type: bool\n    min: 1\n", `A type "bool" does not support min or max`},
+		{"bad bound", "variables:\n  - name: A\n    type: duration\n    max: soon\n", `A bound "soon"`},
+		{"bad pattern", "variables:\n  - name: A\n    type: string\n    pattern: '('\n", "A pattern"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			schemaPath := writeFile(t, "schema.yaml", td.schema)
+			var stdout, stderr bytes.Buffer
+			code := run([]string{"check", "-schema", schemaPath}, &stdout, &stderr)
+			assert.Equal(t, 2, code, "they should be equal")
+			assert.Contains(t, stderr.String(), td.expected)
+		})
+	}
+}
+
+func TestRunUsage(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(nil, &stdout, &stderr), "they should be equal")
+	assert.Contains(t, stderr.String(), "usage: envconv")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"nope"}, &stdout, &stderr), "they should be equal")
+	assert.Contains(t, stderr.String(), `unknown command "nope"`)
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"check"}, &stdout, &stderr), "they should be equal")
+	assert.Contains(t, stderr.String(), "usage: envconv check")
+}
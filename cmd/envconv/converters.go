@@ -0,0 +1,151 @@
+package main
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+)
+
+// converter checks a variable of a single schema type, using the
+// matching envconv functions. Each function returns the converted
+// elements of the variable, flattened into a single slice.
+type converter struct {
+	scalar  func(name string, opts ...envconv.Option) ([]any, error)
+	slice   func(name string, separator string, opts ...envconv.Option) ([]any, error)
+	slice2D func(name string, outer string, inner string, opts ...envconv.Option) ([]any, error)
+
+	// measure returns the quantity of a converted element that is
+	// compared with the min and max bounds, and measureBound parses
+	// those bounds. Both are nil for types that cannot be bounded.
+	measure      func(any) float64
+	measureBound func(string) (float64, error)
+}
+
+// converters maps the name of each schema type to its converter.
+var converters = map[string]converter{
+	"int":      numberConverter(envconv.ToInt, envconv.ToIntSlice, envconv.ToIntSlice2D),
+	"int8":     numberConverter(envconv.ToInt8, envconv.ToInt8Slice, envconv.ToInt8Slice2D),
+	"int16":    numberConverter(envconv.ToInt16, envconv.ToInt16Slice, envconv.ToInt16Slice2D),
+	"int32":    numberConverter(envconv.ToInt32, envconv.ToInt32Slice, envconv.ToInt32Slice2D),
+	"int64":    numberConverter(envconv.ToInt64, envconv.ToInt64Slice, envconv.ToInt64Slice2D),
+	"uint":     numberConverter(envconv.ToUint, envconv.ToUintSlice, envconv.ToUintSlice2D),
+	"uint8":    numberConverter(envconv.ToUint8, envconv.ToUint8Slice, envconv.ToUint8Slice2D),
+	"uint16":   numberConverter(envconv.ToUint16, envconv.ToUint16Slice, envconv.ToUint16Slice2D),
+	"uint32":   numberConverter(envconv.ToUint32, envconv.ToUint32Slice, envconv.ToUint32Slice2D),
+	"uint64":   numberConverter(envconv.ToUint64, envconv.ToUint64Slice, envconv.ToUint64Slice2D),
+	"float32":  numberConverter(envconv.ToFloat32, envconv.ToFloat32Slice, envconv.ToFloat32Slice2D),
+	"float64":  numberConverter(envconv.ToFloat64, envconv.ToFloat64Slice, envconv.ToFloat64Slice2D),
+	"byte":     numberConverter(envconv.ToByte, nil, nil),
+	"bool":     {scalar: scalar(envconv.ToBool), slice: slice(envconv.ToBoolSlice), slice2D: slice2D(envconv.ToBoolSlice2D)},
+	"json":     {scalar: scalar(envconv.ToJSON[any])},
+	"duration": durationConverter(),
+	"string":   stringConverter(),
+}
+
+// number is the set of types that can be bounded numerically.
+type number interface {
+	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64 | float32 | float64
+}
+
+// numberConverter returns the converter for a numeric type. The slice
+// functions may be nil if the type does not support them.
+func numberConverter[T number](
+	toScalar func(string, ...envconv.Option) (T, error),
+	toSlice func(string, string, ...envconv.Option) ([]T, error),
+	toSlice2D func(string, string, string, ...envconv.Option) ([][]T, error),
+) converter {
+	c := converter{
+		scalar: scalar(toScalar),
+		measure: func(v any) float64 {
+			return float64(v.(T))
+		},
+		measureBound: func(bound string) (float64, error) {
+			return strconv.ParseFloat(bound, 64)
+		},
+	}
+	if toSlice != nil {
+		c.slice = slice(toSlice)
+	}
+	if toSlice2D != nil {
+		c.slice2D = slice2D(toSlice2D)
+	}
+	return c
+}
+
+// durationConverter returns the converter for time.Duration, which is
+// bounded by durations such as "1s".
+func durationConverter() converter {
+	return converter{
+		scalar:  scalar(envconv.ToDuration),
+		slice:   slice(envconv.ToDurationSlice),
+		slice2D: slice2D(envconv.ToDurationSlice2D),
+		measure: func(v any) float64 {
+			return float64(v.(time.Duration))
+		},
+		measureBound: func(bound string) (float64, error) {
+			d, err := time.ParseDuration(bound)
+			return float64(d), err
+		},
+	}
+}
+
+// stringConverter returns the converter for string, which is bounded
+// by its length.
+func stringConverter() converter {
+	return converter{
+		scalar:  scalar(envconv.ToString),
+		slice:   slice(envconv.ToStringSlice),
+		slice2D: slice2D(envconv.ToStringSlice2D),
+		measure: func(v any) float64 {
+			return float64(len(v.(string)))
+		},
+		measureBound: func(bound string) (float64, error) {
+			return strconv.ParseFloat(bound, 64)
+		},
+	}
+}
+
+// scalar adapts a single value envconv function to a converter function.
+func scalar[T any](fn func(string, ...envconv.Option) (T, error)) func(string, ...envconv.Option) ([]any, error) {
+	return func(name string, opts ...envconv.Option) ([]any, error) {
+		v, err := fn(name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return []any{v}, nil
+	}
+}
+
+// slice adapts a slice envconv function to a converter function.
+func slice[T any](fn func(string, string, ...envconv.Option) ([]T, error)) func(string, string, ...envconv.Option) ([]any, error) {
+	return func(name string, separator string, opts ...envconv.Option) ([]any, error) {
+		values, err := fn(name, separator, opts...)
+		if err != nil {
+			return nil, err
+		}
+		elements := make([]any, len(values))
+		for i, v := range values {
+			elements[i] = v
+		}
+		return elements, nil
+	}
+}
+
+// slice2D adapts a nested slice envconv function to a converter
+// function, flattening the result.
+func slice2D[T any](fn func(string, string, string, ...envconv.Option) ([][]T, error)) func(string, string, string, ...envconv.Option) ([]any, error) {
+	return func(name string, outer string, inner string, opts ...envconv.Option) ([]any, error) {
+		rows, err := fn(name, outer, inner, opts...)
+		if err != nil {
+			return nil, err
+		}
+		var elements []any
+		for _, row := range rows {
+			for _, v := range row {
+				elements = append(elements, v)
+			}
+		}
+		return elements, nil
+	}
+}
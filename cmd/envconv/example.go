@@ -0,0 +1,132 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+)
+
+// schemaTypes maps the name of each schema type to the Go type that
+// the variable is declared as by the example subcommand.
+var schemaTypes = map[string]reflect.Type{
+	"int":      reflect.TypeOf(int(0)),
+	"int8":     reflect.TypeOf(int8(0)),
+	"int16":    reflect.TypeOf(int16(0)),
+	"int32":    reflect.TypeOf(int32(0)),
+	"int64":    reflect.TypeOf(int64(0)),
+	"uint":     reflect.TypeOf(uint(0)),
+	"uint8":    reflect.TypeOf(uint8(0)),
+	"uint16":   reflect.TypeOf(uint16(0)),
+	"uint32":   reflect.TypeOf(uint32(0)),
+	"uint64":   reflect.TypeOf(uint64(0)),
+	"float32":  reflect.TypeOf(float32(0)),
+	"float64":  reflect.TypeOf(float64(0)),
+	"byte":     reflect.TypeOf(byte(0)),
+	"bool":     reflect.TypeOf(false),
+	"json":     reflect.TypeOf(json.RawMessage(nil)),
+	"duration": reflect.TypeOf(time.Duration(0)),
+	"string":   reflect.TypeOf(""),
+}
+
+// runExample implements the example subcommand.
+func runExample(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("example", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: envconv example -schema FILE [-existing FILE]")
+		flags.PrintDefaults()
+	}
+	schemaPath := flags.String("schema", "", "read the JSON or YAML schema from `file`")
+	existingPath := flags.String("existing", "", "keep the values assigned in the existing .env.example `file`")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if *schemaPath == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return 2
+	}
+
+	s, err := loadSchema(*schemaPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "envconv: %v\n", err)
+		return 2
+	}
+	vars, err := schemaVarSet(s)
+	if err != nil {
+		fmt.Fprintf(stderr, "envconv: %v\n", err)
+		return 2
+	}
+
+	var existing io.Reader
+	if *existingPath != "" {
+		contents, err := os.ReadFile(*existingPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "envconv: %v\n", err)
+			return 2
+		}
+		existing = strings.NewReader(string(contents))
+	}
+	if err := vars.WriteEnvExample(stdout, existing); err != nil {
+		fmt.Fprintf(stderr, "envconv: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// schemaVarSet returns a VarSet declaring each variable in the passed
+// schema. The variables are declared as the fields of a struct built
+// to match the schema, with the same tags a hand written
+// configuration struct would use, and are described with
+// the names of their schema types.
+func schemaVarSet(s *schema) (*envconv.VarSet, error) {
+	fields := make([]reflect.StructField, len(s.Variables))
+	for i, v := range s.Variables {
+		t := schemaTypes[v.Type]
+		tags := []string{"env:" + strconv.Quote(v.Name)}
+		switch {
+		case v.Type == "json":
+			tags = append(tags, `format:"json"`)
+		case v.InnerSeparator != "":
+			t = reflect.SliceOf(reflect.SliceOf(t))
+			tags = append(tags, "separator:"+strconv.Quote(v.Separator), "inner_separator:"+strconv.Quote(v.InnerSeparator))
+		case v.Separator != "":
+			t = reflect.SliceOf(t)
+			tags = append(tags, "separator:"+strconv.Quote(v.Separator))
+		}
+		if v.Description != "" {
+			tags = append(tags, "usage:"+strconv.Quote(v.Description))
+		}
+		if v.Default != nil {
+			tags = append(tags, "default:"+strconv.Quote(*v.Default))
+		}
+		if v.Required {
+			tags = append(tags, `required:"true"`)
+		}
+		if v.Secret {
+			tags = append(tags, `secret:"true"`)
+		}
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("Field%d", i),
+			Type: t,
+			Tag:  reflect.StructTag(strings.Join(tags, " ")),
+		}
+	}
+
+	vars := envconv.NewVarSet("schema")
+	cfg := reflect.New(reflect.StructOf(fields))
+	if err := vars.Struct(cfg.Interface()); err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	for _, v := range s.Variables {
+		vars.Lookup(v.Name).Type = v.typeName()
+	}
+	return vars, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testExampleSchema = `
+variables:
+  - name: HTTP_PORT
+    type: uint16
+    description: port to listen on
+    default: "8080"
+  - name: HTTP_ORIGINS
+    type: string
+    separator: ","
+    default: a.example,b.example
+  - name: DB_PASSWORD
+    type: string
+    description: database password
+    required: true
+    secret: true
+  - name: DB_TIMEOUT
+    type: duration
+    default: 5s
+  - name: FEATURES
+    type: json
+`
+
+func TestExample(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testExampleSchema)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"example", "-schema", schemaPath}, &stdout, &stderr)
+	assert.Equal(t, 0, code, "they should be equal")
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, `# (json, default "null")
+FEATURES=null
+
+# DB
+
+# database password
+# (string, required, secret)
+DB_PASSWORD=
+# (duration, default "5s")
+DB_TIMEOUT=5s
+
+# HTTP
+
+# ([]string, default "a.example,b.example")
+HTTP_ORIGINS=a.example,b.example
+# port to listen on
+# (uint16, default "8080")
+HTTP_PORT=8080
+`, stdout.String(), "they should be equal")
+}
+
+func TestExampleExisting(t *testing.T) {
+	schemaPath := writeFile(t, "schema.yaml", testExampleSchema)
+	existingPath := writeFile(t, ".env.example", "HTTP_PORT=9090\nDB_PASSWORD=s3cr3t\nLEGACY=1\n")
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"example", "-schema", schemaPath, "-existing", existingPath}, &stdout, &stderr)
+	assert.Equal(t, 0, code, "they should be equal")
+	assert.Contains(t, stdout.String(), "\nHTTP_PORT=9090\n")
+	assert.Contains(t, stdout.String(), "\nDB_PASSWORD=s3cr3t\n")
+	assert.Contains(t, stdout.String(), "# Not declared\n\nLEGACY=1\n")
+}
+
+func TestExampleErrors(t *testing.T) {
+	testData := []struct {
+		name     string
+		args     []string
+		schema   string
+		expected string
+	}{
+		{"no schema", nil, "", "usage: envconv example"},
+		{"invalid default", nil, "variables:\n  - name: A\n    type: int\n    default: eighty\n", "invalid default"},
+		{"invalid schema", nil, "variables:\n  - name: A\n    type: complex128\n", `A has unknown type "complex128"`},
+		{"missing existing", []string{"-existing", "/nonexistent/.env.example"}, "variables: []\n", "no such file"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			args := append([]string{"example"}, td.args...)
+			if td.schema != "" {
+				args = append(args, "-schema", writeFile(t, "schema.yaml", td.schema))
+			}
+			var stdout, stderr bytes.Buffer
+			assert.Equal(t, 2, run(args, &stdout, &stderr), "they should be equal")
+			assert.Contains(t, stderr.String(), td.expected)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+// Command envconv provides tools for working with the environment
+// variables read by programs that use the envconv package.
+//
+// Usage:
+//
+//	envconv check -schema FILE [-env-file FILE] [-prefix PREFIX]
+//	envconv example -schema FILE [-existing FILE]
+//
+// The check subcommand validates the current environment, or the
+// contents of a .env file, against a schema. It prints a table of
+// any problems found and exits with status 1 if there are any.
+// With -prefix, any variable starting with PREFIX that is not
+// in the schema is also reported, as a likely misspelling.
+//
+// The example subcommand writes a commented .env.example file
+// describing every variable in a schema, with its description,
+// type, default and whether it is required, to standard output.
+// With -existing, the values assigned in a previous version of
+// the file are kept, so that re-running it never loses them.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: envconv <command> [arguments]
+
+commands:
+  check    validate the environment against a schema
+  example  write a .env.example file describing a schema
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the subcommand named by the first argument, writing
+// its output to stdout and stderr, and returns the exit status.
+func run(args []string, stdout io.Writer, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	switch args[0] {
+	case "check":
+		return runCheck(args[1:], stdout, stderr)
+	case "example":
+		return runExample(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+
+	fmt.Fprintf(stderr, "envconv: unknown command %q\n\n%s", args[0], usage)
+	return 2
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schema lists the environment variables checked by the check
+// subcommand, and described by the example subcommand. It is
+// read from a YAML file, or from a JSON file, as JSON is
+// also valid YAML.
+type schema struct {
+	Variables []variable `yaml:"variables"`
+}
+
+// variable describes a single environment variable in a schema.
+//
+// Type is the name of any type supported by the envconv package, such
+// as "int", "uint16", "duration" or "json". Setting Separator checks
+// the variable as a slice of that type, and also setting
+// InnerSeparator checks it as a nested slice.
+//
+// Min and Max bound numbers and durations, or the length of strings.
+// Enum lists the values each element may take, and Pattern is a
+// regular expression that each element must match.
+//
+// Description and Default are only used by the example subcommand,
+// which writes them to the .env.example file. Default is given as
+// text, in the form the variable itself would be set to.
+type variable struct {
+	Name           string   `yaml:"name"`
+	Type           string   `yaml:"type"`
+	Description    string   `yaml:"description"`
+	Default        *string  `yaml:"default"`
+	Separator      string   `yaml:"separator"`
+	InnerSeparator string   `yaml:"inner_separator"`
+	Required       bool     `yaml:"required"`
+	Secret         bool     `yaml:"secret"`
+	Min            *string  `yaml:"min"`
+	Max            *string  `yaml:"max"`
+	Enum           []string `yaml:"enum"`
+	Pattern        string   `yaml:"pattern"`
+
+	pattern *regexp.Regexp
+}
+
+// typeName returns the schema type of the variable, prefixed with
+// "[]" for a slice or "[][]" for a nested slice.
+func (v variable) typeName() string {
+	switch {
+	case v.InnerSeparator != "":
+		return "[][]" + v.Type
+	case v.Separator != "":
+		return "[]" + v.Type
+	}
+	return v.Type
+}
+
+// readSchema reads and validates a schema from r.
+func readSchema(r io.Reader) (*schema, error) {
+	var s schema
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&s); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range s.Variables {
+		v := &s.Variables[i]
+		if v.Name == "" {
+			return nil, fmt.Errorf("invalid schema: variable %d has no name", i)
+		}
+		if seen[v.Name] {
+			return nil, fmt.Errorf("invalid schema: %s is listed more than once", v.Name)
+		}
+		seen[v.Name] = true
+
+		c, ok := converters[v.Type]
+		switch {
+		case !ok:
+			return nil, fmt.Errorf("invalid schema: %s has unknown type %q", v.Name, v.Type)
+		case v.InnerSeparator != "" && v.Separator == "":
+			return nil, fmt.Errorf("invalid schema: %s has an inner_separator but no separator", v.Name)
+		case v.InnerSeparator != "" && c.slice2D == nil:
+			return nil, fmt.Errorf("invalid schema: %s type %q cannot be a nested slice", v.Name, v.Type)
+		case v.Separator != "" && c.slice == nil:
+			return nil, fmt.Errorf("invalid schema: %s type %q cannot be a slice", v.Name, v.Type)
+		case (v.Min != nil || v.Max != nil) && c.measure == nil:
+			return nil, fmt.Errorf("invalid schema: %s type %q does not support min or max", v.Name, v.Type)
+		}
+
+		if v.Pattern != "" {
+			pattern, err := regexp.Compile(v.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid schema: %s pattern: %w", v.Name, err)
+			}
+			v.pattern = pattern
+		}
+		for _, bound := range []*string{v.Min, v.Max} {
+			if bound == nil {
+				continue
+			}
+			if _, err := c.measureBound(*bound); err != nil {
+				return nil, fmt.Errorf("invalid schema: %s bound %q: %w", v.Name, *bound, err)
+			}
+		}
+	}
+	return &s, nil
+}
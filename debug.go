@@ -0,0 +1,108 @@
+package envconv
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"sort"
+)
+
+// ResolvedVar describes how a single environment variable was
+// resolved, combining its declaration in a VarSet with its
+// Provenance in a Report. Fields that only one of those
+// sources knows about are left empty when it is missing.
+//
+// Value is the current value, and Default the default value, both
+// redacted for a secret variable.
+// An Origin of OriginDefault with an Error means that the
+// default was used as a fallback because of that error.
+type ResolvedVar struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Usage    string `json:"usage,omitempty"`
+	Default  string `json:"default,omitempty"`
+	Required bool   `json:"required,omitempty"`
+	Secret   bool   `json:"secret,omitempty"`
+	Value    string `json:"value"`
+	Origin   Origin `json:"origin,omitempty"`
+	From     string `json:"from,omitempty"`
+	Location string `json:"location,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Resolve returns a description of every variable declared in s or
+// recorded in r, in lexicographical order of name. Either s or r
+// may be nil.
+func Resolve(s *VarSet, r *Report) []ResolvedVar {
+	resolved := map[string]ResolvedVar{}
+	if s != nil {
+		s.VisitAll(func(v *Var) {
+			value, defValue := v.String(), v.DefValue
+			if v.Secret && value != "" {
+				value = redacted
+			}
+			if v.Secret && defValue != "" {
+				defValue = redacted
+			}
+			resolved[v.Name] = ResolvedVar{
+				Name:     v.Name,
+				Type:     v.Type,
+				Usage:    v.Usage,
+				Default:  defValue,
+				Required: v.Required,
+				Secret:   v.Secret,
+				Value:    value,
+			}
+		})
+	}
+	if r != nil {
+		for _, p := range r.Entries() {
+			rv, declared := resolved[p.Name]
+			if !declared {
+				rv = ResolvedVar{Name: p.Name, Secret: p.Secret, Value: p.Value}
+			}
+			rv.Origin, rv.From, rv.Location, rv.Error = p.Origin, p.From, p.Location, p.Error
+			resolved[p.Name] = rv
+		}
+	}
+
+	vars := make([]ResolvedVar, 0, len(resolved))
+	for _, rv := range resolved {
+		vars = append(vars, rv)
+	}
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].Name < vars[j].Name
+	})
+	return vars
+}
+
+// Handler returns an http.Handler that serves the result of
+// Resolve(s, r) as a JSON array. It is intended to be mounted on
+// an administrative port, so that the configuration of a running
+// process can be inspected. Secret values are always redacted.
+func Handler(s *VarSet, r *Report) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(Resolve(s, r))
+	})
+}
+
+// Publish publishes the result of Resolve(s, r) as an expvar
+// variable with the passed name, so that it is served by the
+// expvar handler along with the other published variables.
+// It is resolved again each time it is read. As with
+// expvar.Publish, Publish panics if the name is
+// already in use.
+func Publish(name string, s *VarSet, r *Report) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return Resolve(s, r)
+	}))
+}
@@ -0,0 +1,102 @@
+package envconv_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDebugConfig returns a parsed VarSet and the Report it recorded
+// into, along with one variable loaded outside of the VarSet.
+func newDebugConfig(t *testing.T) (*envconv.VarSet, *envconv.Report) {
+	os.Setenv("TEST_DEBUG_PORT", "9090")
+	os.Setenv("TEST_DEBUG_WORKERS", "many")
+	os.Setenv("TEST_DEBUG_PASSWORD", "hunter2")
+	os.Setenv("TEST_DEBUG_OLD_REGION", "eu-west-1")
+	os.Unsetenv("TEST_DEBUG_REGION")
+	os.Unsetenv("TEST_DEBUG_TIMEOUT")
+
+	r := envconv.NewReport()
+	s := envconv.NewVarSet("test")
+	s.Int("TEST_DEBUG_PORT", 8080, "listen port", envconv.Record(r))
+	s.Int("TEST_DEBUG_WORKERS", 4, "worker count", envconv.Record(r))
+	s.String("TEST_DEBUG_TIMEOUT", "5s", "request timeout", envconv.Record(r))
+	s.Secret("TEST_DEBUG_PASSWORD", "database password", envconv.Record(r))
+	assert.Error(t, s.Parse(), "there should be an error")
+
+	envconv.ToString("TEST_DEBUG_REGION", envconv.Aliases("TEST_DEBUG_OLD_REGION"), envconv.Record(r))
+	return s, r
+}
+
+func TestResolve(t *testing.T) {
+	s, r := newDebugConfig(t)
+
+	assert.Equal(t, []envconv.ResolvedVar{
+		{Name: "TEST_DEBUG_PASSWORD", Type: "envconv.Secret", Usage: "database password", Secret: true, Value: "[REDACTED]", Origin: envconv.OriginEnvironment},
+		{Name: "TEST_DEBUG_PORT", Type: "int", Usage: "listen port", Default: "8080", Value: "9090", Origin: envconv.OriginEnvironment},
+		{Name: "TEST_DEBUG_REGION", Value: "eu-west-1", Origin: envconv.OriginEnvironment, From: "TEST_DEBUG_OLD_REGION"},
+		{Name: "TEST_DEBUG_TIMEOUT", Type: "string", Usage: "request timeout", Default: "5s", Value: "5s", Origin: envconv.OriginDefault, Error: "TEST_DEBUG_TIMEOUT: unknown environment variable"},
+		{Name: "TEST_DEBUG_WORKERS", Type: "int", Usage: "worker count", Default: "4", Value: "4", Origin: envconv.OriginDefault, Error: `TEST_DEBUG_WORKERS: cannot convert "many" to int: strconv.ParseInt: parsing "many": invalid syntax`},
+	}, envconv.Resolve(s, r), "they should be equal")
+
+	t.Run("without a report", func(t *testing.T) {
+		v := envconv.Resolve(s, nil)
+		assert.Len(t, v, 4)
+		assert.Equal(t, envconv.Origin(""), v[0].Origin, "they should be equal")
+	})
+
+	t.Run("without a VarSet", func(t *testing.T) {
+		assert.Len(t, envconv.Resolve(nil, r), 5)
+	})
+
+	t.Run("secret default", func(t *testing.T) {
+		s := envconv.NewVarSet("test")
+		s.String("TEST_DEBUG_DB_PASSWORD", "hunter2", "", envconv.Redact())
+		s.Lookup("TEST_DEBUG_DB_PASSWORD").DefValue = "hunter2"
+		assert.Equal(t, []envconv.ResolvedVar{
+			{Name: "TEST_DEBUG_DB_PASSWORD", Type: "string", Default: "[REDACTED]", Secret: true, Value: "[REDACTED]"},
+		}, envconv.Resolve(s, nil), "they should be equal")
+	})
+}
+
+func TestHandler(t *testing.T) {
+	s, r := newDebugConfig(t)
+	handler := envconv.Handler(s, r)
+
+	t.Run("GET", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/config", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code, "they should be equal")
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"), "they should be equal")
+		assert.NotContains(t, w.Body.String(), "hunter2")
+
+		var vars []envconv.ResolvedVar
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &vars), "there should be no error")
+		assert.Equal(t, envconv.Resolve(s, r), vars, "they should be equal")
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/debug/config", nil))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "they should be equal")
+		assert.Equal(t, "GET, HEAD", w.Header().Get("Allow"), "they should be equal")
+	})
+}
+
+func TestPublish(t *testing.T) {
+	s, r := newDebugConfig(t)
+	envconv.Publish("test_envconv_config", s, r)
+
+	var vars []envconv.ResolvedVar
+	assert.NoError(t, json.Unmarshal([]byte(expvar.Get("test_envconv_config").String()), &vars), "there should be no error")
+	assert.Equal(t, envconv.Resolve(s, r), vars, "they should be equal")
+	assert.NotContains(t, expvar.Get("test_envconv_config").String(), "hunter2")
+}
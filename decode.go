@@ -0,0 +1,506 @@
+package envconv
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// fieldLoader holds the functions that load a struct field of a single
+// type, as a value, as a slice split by a separator, or as a nested
+// slice. The slice functions are nil for types without them.
+type fieldLoader struct {
+	scalar  func(string, ...Option) (any, error)
+	slice   func(string, string, ...Option) (any, error)
+	slice2D func(string, string, string, ...Option) (any, error)
+}
+
+// newFieldLoader returns a fieldLoader calling the passed To* functions.
+func newFieldLoader[T any](scalar func(string, ...Option) (T, error), slice func(string, string, ...Option) ([]T, error), slice2D func(string, string, string, ...Option) ([][]T, error)) fieldLoader {
+	return fieldLoader{
+		scalar: func(varName string, opts ...Option) (any, error) {
+			return scalar(varName, opts...)
+		},
+		slice: func(varName string, separator string, opts ...Option) (any, error) {
+			return slice(varName, separator, opts...)
+		},
+		slice2D: func(varName string, outerSeparator string, innerSeparator string, opts ...Option) (any, error) {
+			return slice2D(varName, outerSeparator, innerSeparator, opts...)
+		},
+	}
+}
+
+// fieldLoaders maps each type that a struct field, or the elements of
+// a slice field, can be decoded into to the functions that load it.
+var fieldLoaders = map[reflect.Type]fieldLoader{
+	reflect.TypeOf(false):      newFieldLoader(ToBool, ToBoolSlice, ToBoolSlice2D),
+	durationType:               newFieldLoader(ToDuration, ToDurationSlice, ToDurationSlice2D),
+	reflect.TypeOf(float32(0)): newFieldLoader(ToFloat32, ToFloat32Slice, ToFloat32Slice2D),
+	reflect.TypeOf(float64(0)): newFieldLoader(ToFloat64, ToFloat64Slice, ToFloat64Slice2D),
+	reflect.TypeOf(int(0)):     newFieldLoader(ToInt, ToIntSlice, ToIntSlice2D),
+	reflect.TypeOf(int8(0)):    newFieldLoader(ToInt8, ToInt8Slice, ToInt8Slice2D),
+	reflect.TypeOf(int16(0)):   newFieldLoader(ToInt16, ToInt16Slice, ToInt16Slice2D),
+	reflect.TypeOf(int32(0)):   newFieldLoader(ToInt32, ToInt32Slice, ToInt32Slice2D),
+	reflect.TypeOf(int64(0)):   newFieldLoader(ToInt64, ToInt64Slice, ToInt64Slice2D),
+	reflect.TypeOf(""):         newFieldLoader(ToString, ToStringSlice, ToStringSlice2D),
+	reflect.TypeOf(uint(0)):    newFieldLoader(ToUint, ToUintSlice, ToUintSlice2D),
+	reflect.TypeOf(uint8(0)):   newFieldLoader(ToUint8, ToUint8Slice, ToUint8Slice2D),
+	reflect.TypeOf(uint16(0)):  newFieldLoader(ToUint16, ToUint16Slice, ToUint16Slice2D),
+	reflect.TypeOf(uint32(0)):  newFieldLoader(ToUint32, ToUint32Slice, ToUint32Slice2D),
+	reflect.TypeOf(uint64(0)):  newFieldLoader(ToUint64, ToUint64Slice, ToUint64Slice2D),
+	byteSliceType: {
+		scalar: func(varName string, opts ...Option) (any, error) {
+			return ToByteSlice(varName, opts...)
+		},
+	},
+	secretType: {
+		scalar: func(varName string, opts ...Option) (any, error) {
+			return ToSecret(varName, opts...)
+		},
+	},
+}
+
+// kindTypes maps each kind of value to the type whose loader is used
+// for a field of a named type of that kind, such as a type Level int.
+var kindTypes = map[reflect.Kind]reflect.Type{
+	reflect.Bool:    reflect.TypeOf(false),
+	reflect.Float32: reflect.TypeOf(float32(0)),
+	reflect.Float64: reflect.TypeOf(float64(0)),
+	reflect.Int:     reflect.TypeOf(int(0)),
+	reflect.Int8:    reflect.TypeOf(int8(0)),
+	reflect.Int16:   reflect.TypeOf(int16(0)),
+	reflect.Int32:   reflect.TypeOf(int32(0)),
+	reflect.Int64:   reflect.TypeOf(int64(0)),
+	reflect.String:  reflect.TypeOf(""),
+	reflect.Uint:    reflect.TypeOf(uint(0)),
+	reflect.Uint8:   reflect.TypeOf(uint8(0)),
+	reflect.Uint16:  reflect.TypeOf(uint16(0)),
+	reflect.Uint32:  reflect.TypeOf(uint32(0)),
+	reflect.Uint64:  reflect.TypeOf(uint64(0)),
+}
+
+// emptyPolicies maps the values of the empty tag to the EmptyPolicy
+// that each selects.
+var emptyPolicies = map[string]EmptyPolicy{
+	"value": EmptyAsValue,
+	"unset": EmptyAsUnset,
+	"error": EmptyAsError,
+	"zero":  EmptyAsZero,
+}
+
+// structField is a tagged struct field to be declared as a variable.
+type structField struct {
+	name   string // name of the variable, including any prefix
+	prefix string // prefix added to the names of the field
+	path   string // path to the field, such as "Config.DB.Host", for errors
+	value  reflect.Value
+	tag    reflect.StructTag
+}
+
+// Struct declares a variable for each tagged field of the struct pointed
+// to by ptr, binding it to that field, in the same way as the typed
+// declaration methods. The fields hold their default values on return,
+// and are filled in when the VarSet is parsed. The passed Options
+// apply to every field, along with those set by its tags:
+//
+//	env:"PORT"             the name of the variable
+//	default:"8080"         the default value, converted as the variable would be,
+//	                       in place of the current value of the field
+//	usage:"text"           the usage string of the variable
+//	required:"true"        the Required option
+//	secret:"true"          the Redact option, which is implied for a Secret field
+//	aliases:"DB_HOST"      the Aliases option, with a comma separated list
+//	                       of names, each with the same prefix as the variable
+//	empty:"unset"          the EmptyPolicy, one of value, unset, error or zero,
+//	                       in place of any passed with WithEmpty
+//	separator:","          split a slice field by the separator
+//	inner_separator:";"    split a nested slice field by separator, and then
+//	                       each element by inner_separator
+//	format:"json"          decode the field from JSON, as ToJSON does
+//
+// A field can be of any type supported by the To* functions, a named
+// type based on one of them, or a slice or nested slice of them. A
+// field of any type can be decoded with format:"json".
+//
+// A struct field without an env tag is walked in turn, with the value
+// of its prefix tag, if any, added to the names of its variables.
+// Any other field without an env tag, or tagged env:"-", is
+// ignored. The Prefix option adds a prefix to every name.
+// WriteEnvExample groups the variables by their prefix.
+func (s *VarSet) Struct(ptr any, opts ...Option) error {
+	v, err := structValue(ptr)
+	if err != nil {
+		return err
+	}
+
+	var fields []structField
+	if err := collectFields(v, newOptions(opts).prefix, v.Type().Name(), &fields); err != nil {
+		return err
+	}
+
+	bindings := make([]binding, len(fields))
+	fieldOpts := make([][]Option, len(fields))
+	seen := map[string]bool{}
+	for i, f := range fields {
+		if _, exists := s.vars[f.name]; exists || seen[f.name] {
+			return fmt.Errorf("envconv: field %s: variable redefined: %s", f.path, f.name)
+		}
+		seen[f.name] = true
+
+		if fieldOpts[i], err = f.options(opts); err != nil {
+			return err
+		}
+		if bindings[i], err = f.binding(fieldOpts[i]); err != nil {
+			return err
+		}
+	}
+
+	for i, f := range fields {
+		s.bind(f.name, f.tag.Get("usage"), bindings[i], fieldOpts[i])
+		s.vars[f.name].group = strings.TrimRight(f.prefix, "_")
+	}
+	return nil
+}
+
+// structValue returns the struct pointed to by ptr, or an error if
+// ptr is not a non-nil pointer to a struct.
+func structValue(ptr any) (reflect.Value, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("envconv: cannot decode into %T, which is not a pointer to a struct", ptr)
+	}
+	return v.Elem(), nil
+}
+
+// collectFields appends the tagged fields of the passed struct, and
+// of any nested structs, to fields.
+func collectFields(v reflect.Value, prefix string, path string, fields *[]structField) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fieldPath := path + "." + sf.Name
+		name, tagged := sf.Tag.Lookup("env")
+		switch {
+		case name == "-":
+			continue
+		case !tagged:
+			if sf.IsExported() && sf.Type.Kind() == reflect.Struct && sf.Type != secretType && sf.Tag.Get("format") == "" {
+				if err := collectFields(v.Field(i), prefix+sf.Tag.Get("prefix"), fieldPath, fields); err != nil {
+					return err
+				}
+			}
+			continue
+		case !sf.IsExported():
+			return fmt.Errorf("envconv: field %s is not exported", fieldPath)
+		case name == "":
+			return fmt.Errorf("envconv: field %s has an empty env tag", fieldPath)
+		}
+		*fields = append(*fields, structField{name: prefix + name, prefix: prefix, path: fieldPath, value: v.Field(i), tag: sf.Tag})
+	}
+	return nil
+}
+
+// options returns the passed Options followed by those set by the
+// tags of the field.
+func (f structField) options(opts []Option) ([]Option, error) {
+	opts = opts[:len(opts):len(opts)]
+	required, err := f.boolTag("required")
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		opts = append(opts, Required())
+	}
+	secret, err := f.boolTag("secret")
+	if err != nil {
+		return nil, err
+	}
+	if secret || f.value.Type() == secretType {
+		opts = append(opts, Redact())
+	}
+	if text, ok := f.tag.Lookup("empty"); ok {
+		policy, ok := emptyPolicies[text]
+		if !ok {
+			return nil, fmt.Errorf("envconv: field %s: invalid empty tag %q", f.path, text)
+		}
+		opts = append(opts, WithEmpty(policy))
+	}
+	if text := f.tag.Get("aliases"); text != "" {
+		aliases := strings.Split(text, ",")
+		for i, alias := range aliases {
+			aliases[i] = f.prefix + strings.TrimSpace(alias)
+		}
+		opts = append(opts, Aliases(aliases...))
+	}
+	return opts, nil
+}
+
+// boolTag returns the value of the named boolean tag of the field,
+// which is false if the tag is not set.
+func (f structField) boolTag(key string) (bool, error) {
+	text, ok := f.tag.Lookup(key)
+	if !ok {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(text)
+	if err != nil {
+		return false, fmt.Errorf("envconv: field %s: invalid %s tag %q", f.path, key, text)
+	}
+	return b, nil
+}
+
+// binding returns the binding for the field, choosing how it is loaded
+// and formatted from its type and tags. The default value is parsed
+// from the default tag with the passed Options, if it is set.
+func (f structField) binding(opts []Option) (binding, error) {
+	t := f.value.Type()
+	b := binding{
+		typ:   t.String(),
+		value: f.value.Interface(),
+		get: func() any {
+			return f.value.Interface()
+		},
+		set: func(v any) {
+			if v == nil {
+				f.value.SetZero()
+				return
+			}
+			f.value.Set(reflect.ValueOf(v))
+		},
+	}
+
+	separator, innerSeparator := f.tag.Get("separator"), f.tag.Get("inner_separator")
+	unsupported := fmt.Errorf("envconv: field %s: cannot decode type %s", f.path, t)
+	switch {
+	case f.tag.Get("format") == "json":
+		b.load = func(varName string, opts ...Option) (any, error) {
+			o := newOptions(opts)
+			return convertAs(varName, b.typ, opts, func(value string) (any, error) {
+				ptr := reflect.New(t)
+				if err := decodeJSON(value, ptr.Interface(), o); err != nil {
+					return nil, err
+				}
+				return ptr.Elem().Interface(), nil
+			})
+		}
+		b.format = formatJSON[any]
+		b.encode = encodeJSON[any]
+
+	case innerSeparator != "":
+		if separator == "" {
+			return binding{}, fmt.Errorf("envconv: field %s: inner_separator tag without a separator tag", f.path)
+		}
+		if t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Slice {
+			return binding{}, unsupported
+		}
+		l, ok := loaderFor(t.Elem().Elem())
+		if !ok || l.slice2D == nil {
+			return binding{}, unsupported
+		}
+		b.load = func(varName string, opts ...Option) (any, error) {
+			return l.slice2D(varName, separator, innerSeparator, opts...)
+		}
+		b.format = func(v any) string {
+			return formatList(reflect.ValueOf(v), separator, innerSeparator)
+		}
+		b.encode = func(v any) (string, error) {
+			return format2D(reflect.ValueOf(v), separator, innerSeparator, newOptions(opts).quoted)
+		}
+
+	case separator != "":
+		if t.Kind() != reflect.Slice {
+			return binding{}, unsupported
+		}
+		l, ok := loaderFor(t.Elem())
+		if !ok || l.slice == nil {
+			return binding{}, unsupported
+		}
+		b.load = func(varName string, opts ...Option) (any, error) {
+			return l.slice(varName, separator, opts...)
+		}
+		b.format = func(v any) string {
+			return formatList(reflect.ValueOf(v), separator)
+		}
+		b.encode = func(v any) (string, error) {
+			return encodeSlice(reflect.ValueOf(v), separator, newOptions(opts).quoted)
+		}
+
+	default:
+		l, ok := loaderFor(t)
+		if !ok {
+			return binding{}, unsupported
+		}
+		b.load = l.scalar
+		b.format = func(v any) string {
+			switch v := v.(type) {
+			case Secret:
+				return formatSecret(v)
+			case []byte:
+				return string(v)
+			}
+			return fmt.Sprint(v)
+		}
+		b.encode = func(v any) (string, error) {
+			return Format(v, "")
+		}
+	}
+
+	load := b.load
+	b.load = func(varName string, opts ...Option) (any, error) {
+		v, err := load(varName, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return convertValue(reflect.ValueOf(v), t).Interface(), nil
+	}
+
+	if text, ok := f.tag.Lookup("default"); ok {
+		src := NewEnv(map[string]string{f.name: text})
+		value, err := b.load(f.name, append(opts[:len(opts):len(opts)], FromSource(src), Record(nil))...)
+		if err != nil {
+			return binding{}, fmt.Errorf("envconv: field %s: invalid default: %w", f.path, err)
+		}
+		b.value = value
+	}
+	return b, nil
+}
+
+// loaderFor returns the fieldLoader for the passed type, falling back
+// to the loader of its kind for a named type, such as a type Level
+// int, which is then converted by convertValue.
+func loaderFor(t reflect.Type) (fieldLoader, bool) {
+	if l, ok := fieldLoaders[t]; ok {
+		return l, true
+	}
+	base, ok := kindTypes[t.Kind()]
+	if !ok {
+		return fieldLoader{}, false
+	}
+	return fieldLoaders[base], true
+}
+
+// convertValue converts the passed value, as loaded by a fieldLoader,
+// to the type of the field it is loaded into, converting each
+// element of a slice whose element type differs.
+func convertValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type() == t {
+		return v
+	}
+	if t.Kind() != reflect.Slice || v.Type().ConvertibleTo(t) {
+		return v.Convert(t)
+	}
+	if v.IsNil() {
+		return reflect.Zero(t)
+	}
+	converted := reflect.MakeSlice(t, v.Len(), v.Len())
+	for i := 0; i < v.Len(); i++ {
+		converted.Index(i).Set(convertValue(v.Index(i), t.Elem()))
+	}
+	return converted
+}
+
+// formatList formats the passed slice for display, with its elements
+// separated by the first separator, and the elements of a nested
+// slice by the second.
+func formatList(v reflect.Value, separators ...string) string {
+	elements := make([]string, v.Len())
+	for i := range elements {
+		if len(separators) > 1 {
+			elements[i] = formatList(v.Index(i), separators[1:]...)
+		} else {
+			elements[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+	}
+	return strings.Join(elements, separators[0])
+}
+
+// Decode fills in the tagged fields of the struct pointed to by ptr
+// from the environment, as a VarSet holding only that struct would
+// with Struct and ParseFrom. Fields are configured with struct
+// tags, as described by VarSet.Struct, and the passed Options
+// apply to every field, so that FromSource, for example,
+// decodes the struct from a Snapshot or a dotenv file.
+//
+// A field whose variable is not set keeps its current value, or takes
+// the value of its default tag. Decode returns an error joining
+// every variable that failed to load, and changes no field
+// unless every variable loads.
+func Decode(ptr any, opts ...Option) error {
+	v, err := structValue(ptr)
+	if err != nil {
+		return err
+	}
+
+	decoded := reflect.New(v.Type())
+	decoded.Elem().Set(v)
+	s := NewVarSet(v.Type().Name())
+	if err := s.Struct(decoded.Interface(), opts...); err != nil {
+		return err
+	}
+	if err := s.ParseFrom(nil); err != nil {
+		return err
+	}
+	v.Set(decoded.Elem())
+	return nil
+}
+
+// Encode returns the tagged fields of the passed struct, or of the
+// struct it points to, as an Env that Decode reads back into an
+// equal struct when passed the same Options. Fields are named
+// and formatted following their struct tags, as described by
+// VarSet.Struct, and errors are returned as VarSet.Encode
+// returns them. Secret fields are left out unless
+// includeSecrets is true.
+func Encode(v any, includeSecrets bool, opts ...Option) (*Env, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("envconv: cannot encode %T, which is not a struct", v)
+	}
+
+	encoded := reflect.New(rv.Type())
+	s := NewVarSet(rv.Type().Name())
+	if err := s.Struct(encoded.Interface(), opts...); err != nil {
+		return nil, err
+	}
+	encoded.Elem().Set(rv)
+	return s.Encode(includeSecrets)
+}
+
+// ToStruct returns a struct of type T decoded with Decode, adding
+// prefix to the name of every variable. Fields whose variables are
+// not set take the value of their default tags, or are left as the
+// zero value. As it has the same signature as the To* functions,
+// ToStruct can be passed to NewValue to reload a whole
+// struct at once.
+func ToStruct[T any](prefix string, opts ...Option) (T, error) {
+	var value T
+	if err := Decode(&value, append(opts[:len(opts):len(opts)], Prefix(prefix))...); err != nil {
+		var zero T
+		return zero, err
+	}
+	return value, nil
+}
+
+// Loader decodes structs with a shared set of Options, such as
+// a Source, an EmptyPolicy or a Report, so that the policies for
+// a whole configuration are set in one place.
+type Loader struct {
+	opts []Option
+}
+
+// NewLoader returns a Loader that applies the passed Options to every
+// struct it decodes.
+func NewLoader(opts ...Option) *Loader {
+	return &Loader{opts: opts}
+}
+
+// Decode fills in the tagged fields of the struct pointed to by ptr,
+// as the package level Decode function does, with the Options of
+// the Loader followed by the passed Options.
+func (l *Loader) Decode(ptr any, opts ...Option) error {
+	return Decode(ptr, append(l.opts[:len(l.opts):len(l.opts)], opts...)...)
+}
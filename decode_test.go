@@ -0,0 +1,294 @@
+package envconv_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLevel int
+
+type testDBConfig struct {
+	Host string `env:"HOST" default:"localhost"`
+	Port int    `env:"PORT" default:"5432"`
+}
+
+type testConfig struct {
+	Port     int               `env:"PORT" default:"8080" usage:"port to listen on"`
+	Debug    bool              `env:"DEBUG"`
+	Timeout  time.Duration     `env:"TIMEOUT" default:"5s"`
+	Level    testLevel         `env:"LEVEL"`
+	Hosts    []string          `env:"HOSTS" separator:","`
+	Matrix   [][]int           `env:"MATRIX" separator:";" inner_separator:","`
+	Key      []byte            `env:"KEY"`
+	Token    envconv.Secret    `env:"TOKEN"`
+	Labels   map[string]string `env:"LABELS" format:"json"`
+	DB       testDBConfig      `prefix:"DB_"`
+	Ignored  string
+	Excluded string `env:"-"`
+}
+
+func TestDecode(t *testing.T) {
+	src := envconv.NewEnv(map[string]string{
+		"APP_DEBUG":    "true",
+		"APP_LEVEL":    "3",
+		"APP_HOSTS":    "a, b",
+		"APP_MATRIX":   "1,2;3",
+		"APP_KEY":      "raw",
+		"APP_TOKEN":    "hunter2",
+		"APP_LABELS":   `{"team":"core"}`,
+		"APP_DB_HOST":  "db.internal",
+		"APP_EXCLUDED": "set",
+	})
+
+	cfg := testConfig{Debug: false, Ignored: "kept", Excluded: "kept"}
+	err := envconv.Decode(&cfg, envconv.FromSource(src), envconv.Prefix("APP_"))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 8080, cfg.Port, "they should be equal")
+	assert.Equal(t, true, cfg.Debug, "they should be equal")
+	assert.Equal(t, 5*time.Second, cfg.Timeout, "they should be equal")
+	assert.Equal(t, testLevel(3), cfg.Level, "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts, "they should be equal")
+	assert.Equal(t, [][]int{{1, 2}, {3}}, cfg.Matrix, "they should be equal")
+	assert.Equal(t, []byte("raw"), cfg.Key, "they should be equal")
+	assert.Equal(t, "hunter2", cfg.Token.Reveal(), "they should be equal")
+	assert.Equal(t, map[string]string{"team": "core"}, cfg.Labels, "they should be equal")
+	assert.Equal(t, testDBConfig{Host: "db.internal", Port: 5432}, cfg.DB, "they should be equal")
+	assert.Equal(t, "kept", cfg.Ignored, "they should be equal")
+	assert.Equal(t, "kept", cfg.Excluded, "they should be equal")
+}
+
+func TestDecodeNamedSlices(t *testing.T) {
+	type levels []testLevel
+	type namedConfig struct {
+		Levels   []testLevel   `env:"LEVELS" separator:","`
+		Named    levels        `env:"NAMED" separator:"," default:"4,5"`
+		Matrix   [][]testLevel `env:"MATRIX" separator:";" inner_separator:","`
+		Fallback []testLevel   `env:"FALLBACK" separator:"," default:"7"`
+	}
+	src := envconv.NewEnv(map[string]string{
+		"LEVELS": "1,2",
+		"MATRIX": "1,2;3",
+	})
+
+	var cfg namedConfig
+	assert.NoError(t, envconv.Decode(&cfg, envconv.FromSource(src)), "there should be no error")
+	assert.Equal(t, namedConfig{
+		Levels:   []testLevel{1, 2},
+		Named:    levels{4, 5},
+		Matrix:   [][]testLevel{{1, 2}, {3}},
+		Fallback: []testLevel{7},
+	}, cfg, "they should be equal")
+
+	env, err := envconv.Encode(cfg, false)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "1,2;3", env.Map()["MATRIX"], "they should be equal")
+}
+
+func TestDecodeErrors(t *testing.T) {
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{
+		"PORT":    "eighty",
+		"TIMEOUT": "soon",
+		"DEBUG":   "true",
+	}))
+
+	cfg := testConfig{Debug: false}
+	err := envconv.Decode(&cfg, src)
+	assert.ErrorIs(t, err, strconv.ErrSyntax, "the error should wrap strconv.ErrSyntax")
+	assert.Contains(t, err.Error(), "PORT", "the error should name every variable")
+	assert.Contains(t, err.Error(), "TIMEOUT", "the error should name every variable")
+	assert.Equal(t, testConfig{}, cfg, "no field should change")
+
+	var required struct {
+		Name string `env:"NAME" required:"true"`
+	}
+	assert.ErrorIs(t, envconv.Decode(&required, src), envconv.ErrNotSet, "the error should wrap ErrNotSet")
+
+	testData := []struct {
+		name string
+		ptr  any
+	}{
+		{"not a pointer", testConfig{}},
+		{"not a struct", new(int)},
+		{"nil pointer", (*testConfig)(nil)},
+		{"unsupported type", &struct {
+			C chan int `env:"C"`
+		}{}},
+		{"slice without separator", &struct {
+			Ports []int `env:"PORTS"`
+		}{}},
+		{"inner separator only", &struct {
+			Ports [][]int `env:"PORTS" inner_separator:","`
+		}{}},
+		{"invalid default", &struct {
+			Port int `env:"PORT" default:"eighty"`
+		}{}},
+		{"invalid tag", &struct {
+			Port int `env:"PORT" required:"maybe"`
+		}{}},
+		{"empty name", &struct {
+			Port int `env:""`
+		}{}},
+		{"unexported", &struct {
+			port int `env:"PORT"`
+		}{}},
+		{"redefined", &struct {
+			A int `env:"PORT"`
+			B int `env:"PORT"`
+		}{}},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			assert.Error(t, envconv.Decode(td.ptr, src), "there should be an error")
+		})
+	}
+}
+
+func TestVarSetStruct(t *testing.T) {
+	var cfg testConfig
+	s := envconv.NewVarSet("test")
+	err := s.Struct(&cfg, envconv.Prefix("APP_"))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 8080, cfg.Port, "the field should hold its default")
+
+	v := s.Lookup("APP_PORT")
+	if assert.NotNil(t, v, "the variable should be declared") {
+		assert.Equal(t, "int", v.Type, "they should be equal")
+		assert.Equal(t, "8080", v.DefValue, "they should be equal")
+		assert.Equal(t, "port to listen on", v.Usage, "they should be equal")
+	}
+	assert.Equal(t, "envconv_test.testLevel", s.Lookup("APP_LEVEL").Type, "they should be equal")
+	assert.NotNil(t, s.Lookup("APP_DB_PORT"), "nested fields should be declared with their prefix")
+	assert.Nil(t, s.Lookup("APP_IGNORED"), "untagged fields should not be declared")
+
+	err = s.ParseFrom(envconv.NewEnv(map[string]string{"APP_PORT": "9090", "APP_MATRIX": "1,2;3"}))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 9090, cfg.Port, "they should be equal")
+	assert.Equal(t, "1,2;3", s.Lookup("APP_MATRIX").String(), "they should be equal")
+	assert.Error(t, s.Struct(&cfg, envconv.Prefix("APP_")), "declaring a variable twice should be an error")
+}
+
+func TestToStruct(t *testing.T) {
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{"DB_HOST": "db.internal"}))
+	cfg, err := envconv.ToStruct[testDBConfig]("DB_", src)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, testDBConfig{Host: "db.internal", Port: 5432}, cfg, "they should be equal")
+
+	_, err = envconv.ToStruct[int]("DB_", src)
+	assert.Error(t, err, "there should be an error")
+}
+
+func TestLoader(t *testing.T) {
+	r := envconv.NewReport()
+	l := envconv.NewLoader(envconv.FromSource(envconv.NewEnv(map[string]string{"HOST": "db.internal"})), envconv.Record(r))
+
+	var cfg testDBConfig
+	assert.NoError(t, l.Decode(&cfg), "there should be no error")
+	assert.Equal(t, testDBConfig{Host: "db.internal", Port: 5432}, cfg, "they should be equal")
+	host, _ := r.Lookup("HOST")
+	assert.Equal(t, envconv.OriginSource, host.Origin, "they should be equal")
+	port, _ := r.Lookup("PORT")
+	assert.Equal(t, envconv.OriginDefault, port.Origin, "they should be equal")
+
+	assert.NoError(t, l.Decode(&cfg, envconv.Prefix("APP_")), "there should be no error")
+	assert.Equal(t, "localhost", cfg.Host, "the passed Options should follow those of the Loader")
+}
+
+func TestDecodeEmptyPolicy(t *testing.T) {
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{
+		"PORT":    "",
+		"WORKERS": "",
+		"RATE":    "",
+	}))
+
+	type config struct {
+		Port    int     `env:"PORT" default:"8080"`
+		Workers int     `env:"WORKERS" default:"4" empty:"zero"`
+		Rate    float64 `env:"RATE" default:"1.5" empty:"error"`
+	}
+
+	var cfg config
+	err := envconv.NewLoader(src).Decode(&cfg)
+	assert.ErrorIs(t, err, strconv.ErrSyntax, "an empty value should fail to convert by default")
+	assert.ErrorIs(t, err, envconv.ErrEmpty, "the empty tag should set the policy of its field")
+
+	err = envconv.NewLoader(src, envconv.WithEmpty(envconv.EmptyAsUnset)).Decode(&cfg)
+	assert.ErrorIs(t, err, envconv.ErrEmpty, "the empty tag should override the policy of the Loader")
+	assert.NotErrorIs(t, err, strconv.ErrSyntax, "the Loader policy should apply to untagged fields")
+
+	var unset struct {
+		Port    int `env:"PORT" default:"8080"`
+		Workers int `env:"WORKERS" default:"4" empty:"zero"`
+	}
+	err = envconv.NewLoader(src, envconv.WithEmpty(envconv.EmptyAsUnset)).Decode(&unset)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 8080, unset.Port, "an empty value should be treated as unset")
+	assert.Equal(t, 0, unset.Workers, "an empty value should be treated as zero")
+
+	var invalid struct {
+		Port int `env:"PORT" empty:"ignore"`
+	}
+	assert.Error(t, envconv.Decode(&invalid, src), "an invalid empty tag should be an error")
+}
+
+func TestDecodeAliases(t *testing.T) {
+	type config struct {
+		Host string `env:"DATABASE_HOST" aliases:"DB_HOST, OLD_DB_HOST"`
+	}
+
+	var deprecated []string
+	onDeprecated := envconv.OnDeprecated(func(alias string, varName string) {
+		deprecated = append(deprecated, alias+" "+varName)
+	})
+
+	var cfg config
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{"APP_OLD_DB_HOST": "db.internal"}))
+	err := envconv.Decode(&cfg, src, envconv.Prefix("APP_"), onDeprecated)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "db.internal", cfg.Host, "they should be equal")
+	assert.Equal(t, []string{"APP_OLD_DB_HOST APP_DATABASE_HOST"}, deprecated, "they should be equal")
+
+	src = envconv.FromSource(envconv.NewEnv(map[string]string{"DATABASE_HOST": "a", "DB_HOST": "b"}))
+	assert.ErrorIs(t, envconv.Decode(&cfg, src), envconv.ErrConflict, "the error should wrap ErrConflict")
+}
+
+func TestDecodeSecret(t *testing.T) {
+	type config struct {
+		Password string         `env:"PASSWORD" secret:"true" usage:"database password"`
+		Port     int            `env:"PORT" secret:"true"`
+		Token    envconv.Secret `env:"TOKEN"`
+	}
+
+	src := envconv.NewEnv(map[string]string{
+		"PASSWORD": "hunter2",
+		"PORT":     "hunter2",
+		"TOKEN":    "s3cr3t",
+	})
+
+	var cfg config
+	err := envconv.Decode(&cfg, envconv.FromSource(src))
+	assert.ErrorIs(t, err, strconv.ErrSyntax, "the error should wrap strconv.ErrSyntax")
+	assert.NotContains(t, err.Error(), "hunter2", "the error should not hold the value")
+
+	s := envconv.NewVarSet("test")
+	assert.NoError(t, s.Struct(&cfg), "there should be no error")
+	assert.NoError(t, s.ParseFrom(envconv.NewEnv(map[string]string{"PASSWORD": "hunter2", "TOKEN": "s3cr3t"})), "there should be no error")
+	assert.Equal(t, "hunter2", cfg.Password, "they should be equal")
+	assert.True(t, s.Lookup("PASSWORD").Secret, "a secret tag should mark the variable secret")
+	assert.True(t, s.Lookup("TOKEN").Secret, "a Secret field should be secret")
+
+	var b strings.Builder
+	assert.NoError(t, s.WriteUsage(&b, envconv.UsageText), "there should be no error")
+	assert.NotContains(t, b.String(), "hunter2", "usage should not hold the value")
+	assert.NotContains(t, b.String(), "s3cr3t", "usage should not hold the value")
+
+	invalid := struct {
+		Password string `env:"PASSWORD" secret:"yes please"`
+	}{}
+	assert.Error(t, envconv.Decode(&invalid), "an invalid secret tag should be an error")
+}
@@ -0,0 +1,113 @@
+package envconv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// DotenvEntry is a single variable assignment read from a dotenv file,
+// along with the line number on which it was found.
+type DotenvEntry struct {
+	Name  string
+	Value string
+	Line  int
+}
+
+// dotenvEscaper escapes the characters that have a special meaning
+// inside a double quoted dotenv value.
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "$", `\$`)
+
+// ParseDotenv reads KEY=VALUE assignments from r, one per line, in
+// the order in which they appear. Blank
+// lines and lines starting with # are skipped, and an optional
+// "export " prefix is allowed. Values may be unquoted, single
+// quoted, which are taken literally, or double quoted, which
+// allow backslash escapes.
+func ParseDotenv(r io.Reader) ([]DotenvEntry, error) {
+	var entries []DotenvEntry
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimSpace(strings.TrimPrefix(text, "export "))
+
+		name, value, ok := strings.Cut(text, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("dotenv line %d: invalid assignment", line)
+		}
+
+		value, err := parseDotenvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("dotenv line %d: %w", line, err)
+		}
+		entries = append(entries, DotenvEntry{Name: name, Value: value, Line: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// parseDotenvValue returns the value of a single dotenv assignment,
+// with any quotes, escapes and trailing comment removed.
+func parseDotenvValue(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return "", ErrUnterminatedQuote
+		}
+		return value[1 : end+1], nil
+
+	case strings.HasPrefix(value, `"`):
+		var b strings.Builder
+		for i := 1; i < len(value); i++ {
+			switch value[i] {
+			case '"':
+				return b.String(), nil
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					switch value[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 'r':
+						b.WriteByte('\r')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(value[i])
+					}
+					continue
+				}
+				b.WriteByte('\\')
+			default:
+				b.WriteByte(value[i])
+			}
+		}
+		return "", ErrUnterminatedQuote
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// formatDotenvValue returns the passed value in a form that
+// ParseDotenv will read back unchanged, double quoting it
+// only where necessary.
+func formatDotenvValue(value string) string {
+	if strings.ContainsAny(value, " #'\"\\$") || strings.ContainsFunc(value, unicode.IsControl) {
+		return `"` + dotenvEscaper.Replace(value) + `"`
+	}
+	return value
+}
@@ -0,0 +1,56 @@
+package envconv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDotenv(t *testing.T) {
+	input := `# comment
+PLAIN=value
+SPACED = spaced value  
+export EXPORTED=yes
+EMPTY=
+COMMENTED=value # trailing comment
+HASH=value#kept
+SINGLE='literal \n $HOME # not a comment'
+DOUBLE="line one\nline \"two\" \$HOME"
+
+`
+	entries, err := envconv.ParseDotenv(strings.NewReader(input))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, []envconv.DotenvEntry{
+		{Name: "PLAIN", Value: "value", Line: 2},
+		{Name: "SPACED", Value: "spaced value", Line: 3},
+		{Name: "EXPORTED", Value: "yes", Line: 4},
+		{Name: "EMPTY", Value: "", Line: 5},
+		{Name: "COMMENTED", Value: "value", Line: 6},
+		{Name: "HASH", Value: "value#kept", Line: 7},
+		{Name: "SINGLE", Value: `literal \n $HOME # not a comment`, Line: 8},
+		{Name: "DOUBLE", Value: "line one\nline \"two\" $HOME", Line: 9},
+	}, entries, "they should be equal")
+}
+
+func TestParseDotenvErrors(t *testing.T) {
+	testData := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"no equals", "A=1\nNOT_AN_ASSIGNMENT\n", "dotenv line 2: invalid assignment"},
+		{"no name", "=value\n", "dotenv line 1: invalid assignment"},
+		{"space in name", "A B=value\n", "dotenv line 1: invalid assignment"},
+		{"unterminated double", `A="value`, "dotenv line 1: unterminated quote"},
+		{"unterminated single", `A='value`, "dotenv line 1: unterminated quote"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			_, err := envconv.ParseDotenv(strings.NewReader(td.input))
+			assert.ErrorContains(t, err, td.expected)
+		})
+	}
+}
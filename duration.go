@@ -1,7 +1,6 @@
 package envconv
 
 import (
-	"strings"
 	"time"
 )
 
@@ -19,34 +18,16 @@ func convertDuration(value string) (time.Duration, error) {
 // converted to a time.Duration. An error will be returned if the
 // environment variable is not found or the conversion to
 // time.Duration fails.
-func ToDuration(varName string) (time.Duration, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return 0, err
-	}
-	return convertDuration(value)
+func ToDuration(varName string, opts ...Option) (time.Duration, error) {
+	return convert(varName, opts, convertDuration)
 }
 
 // ToDurationSlice returns the value of the requested environment variable
 // converted to a slice of time.Durations. An error will be returned
 // if the environment variable is not found or the conversion to
 // time.Duration fails.
-func ToDurationSlice(varName string, separator string) ([]time.Duration, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return []time.Duration{}, err
-	}
-
-	durationStrings := strings.Split(value, separator)
-	var durations = []time.Duration{}
-	for _, duration := range durationStrings {
-		convertedDuration, err := convertDuration(duration)
-		if err != nil {
-			return []time.Duration{}, err
-		}
-		durations = append(durations, convertedDuration)
-	}
-	return durations, nil
+func ToDurationSlice(varName string, separator string, opts ...Option) ([]time.Duration, error) {
+	return convertSlice(varName, separator, opts, convertDuration)
 }
 
 // ToDurationWithDefault returns the value of the requested environment
@@ -54,22 +35,34 @@ func ToDurationSlice(varName string, separator string) ([]time.Duration, error)
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to
 // time.Duration fails.
-func ToDurationWithDefault(varName string, defaultValue time.Duration) time.Duration {
-	value, err := ToDuration(varName)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToDurationWithDefault(varName string, defaultValue time.Duration, opts ...Option) time.Duration {
+	value, err := ToDuration(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToDurationSliceWithDefault returns the value of the requested environment
 // variable converted to a slice of time.Durations. The default value
 // passed as the second parameter will be returned if the environment
 // variable is not found or the conversion to time.Duration fails.
-func ToDurationSliceWithDefault(varName string, separator string, defaultValue []time.Duration) []time.Duration {
-	value, err := ToDurationSlice(varName, separator)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func ToDurationSliceWithDefault(varName string, separator string, defaultValue []time.Duration, opts ...Option) []time.Duration {
+	value, err := ToDurationSlice(varName, separator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
+}
+
+// ToDurationSlice2D returns the value of the requested environment variable
+// converted to a nested slice of time.Durations. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToDurationSlice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]time.Duration, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, convertDuration)
+}
+
+// ToDurationSlice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of time.Durations. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToDurationSlice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]time.Duration, opts ...Option) [][]time.Duration {
+	value, err := ToDurationSlice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
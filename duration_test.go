@@ -95,3 +95,19 @@ func TestToDurationSliceWithDefault(t *testing.T) {
 	}
 	runSliceWithDefaultEmptyTest[time.Duration](t, ",", def, envconv.ToDurationSliceWithDefault)
 }
+
+func TestToDurationSlice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]time.Duration
+		errorExpected bool
+	}{
+		{"TEST_DURATION_SLICE_2D_BACKOFF", "1s, 2s;1m", [][]time.Duration{{time.Second, 2 * time.Second}, {time.Minute}}, false},
+		{"TEST_DURATION_SLICE_2D_NOTADURATION", "1s;2", [][]time.Duration{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToDurationSlice2D)
+	}
+}
@@ -0,0 +1,314 @@
+package envconv_test
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// config holds the values of the variables declared by declareConfig.
+type config struct {
+	Port     int
+	Rate     float64
+	Debug    bool
+	Timeout  time.Duration
+	Name     string
+	Limit    uint64
+	Shards   []int
+	Hosts    []string
+	Password string
+}
+
+// declareConfig declares a variable for each field of a config in the
+// passed VarSet, returning functions that set and get their values.
+// Every value of a config is read back unchanged, as the variables
+// that could be empty use EmptyAsZero, and the hosts are Quoted.
+func declareConfig(s *envconv.VarSet) (func(config), func() config) {
+	zero := envconv.WithEmpty(envconv.EmptyAsZero)
+	port := s.Int("APP_PORT", 8080, "listen port")
+	rate := s.Float64("APP_RATE", 1.5, "requests per second")
+	debug := s.Bool("APP_DEBUG", false, "enable debug output")
+	timeout := s.Duration("APP_TIMEOUT", 5*time.Second, "request timeout")
+	name := s.String("APP_NAME", "app", "service name", zero)
+	limit := s.Uint64("APP_LIMIT", 100, "request limit")
+	shards := s.IntSlice("APP_SHARDS", ",", []int{1}, "shard ids", zero)
+	hosts := s.StringSlice("APP_HOSTS", ",", []string{"localhost"}, "upstream hosts", zero, envconv.Quoted())
+	password := s.Secret("APP_PASSWORD", "database password")
+
+	set := func(c config) {
+		*port, *rate, *debug, *timeout, *name, *limit = c.Port, c.Rate, c.Debug, c.Timeout, c.Name, c.Limit
+		*shards, *hosts, *password = c.Shards, c.Hosts, envconv.NewSecret(c.Password)
+	}
+	get := func() config {
+		return config{*port, *rate, *debug, *timeout, *name, *limit, *shards, *hosts, password.Reveal()}
+	}
+	return set, get
+}
+
+func TestEncode(t *testing.T) {
+	s := envconv.NewVarSet("test")
+	set, _ := declareConfig(s)
+	set(config{
+		Port:     9090,
+		Rate:     0.25,
+		Timeout:  1500 * time.Millisecond,
+		Limit:    100,
+		Shards:   []int{1, 2},
+		Hosts:    []string{"a,b", "c"},
+		Password: "hunter2",
+	})
+
+	env, err := s.Encode(false)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, []string{
+		"APP_DEBUG=false",
+		`APP_HOSTS="a,b",c`,
+		"APP_LIMIT=100",
+		"APP_NAME=",
+		"APP_PORT=9090",
+		"APP_RATE=0.25",
+		"APP_SHARDS=1,2",
+		"APP_TIMEOUT=1.5s",
+	}, env.Environ(), "they should be equal")
+
+	env, err = s.Encode(true)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "hunter2", env.Map()["APP_PASSWORD"], "they should be equal")
+}
+
+func TestEncodeOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		declare func(s *envconv.VarSet)
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "unquoted slice",
+			declare: func(s *envconv.VarSet) {
+				*s.StringSlice("TEST_ENCODE", ",", nil, "") = []string{`say "hi"`, "b"}
+			},
+			want: `say "hi",b`,
+		},
+		{
+			name: "quoted slice",
+			declare: func(s *envconv.VarSet) {
+				*s.StringSlice("TEST_ENCODE", ",", nil, "", envconv.Quoted()) = []string{`say "hi"`, "b"}
+			},
+			want: `"say \"hi\"",b`,
+		},
+		{
+			name: "separator in an unquoted slice",
+			declare: func(s *envconv.VarSet) {
+				*s.StringSlice("TEST_ENCODE", ",", nil, "") = []string{"a,b", "c"}
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty element in an unquoted slice",
+			declare: func(s *envconv.VarSet) {
+				*s.StringSlice("TEST_ENCODE", ",", nil, "", envconv.DropEmpty()) = []string{"a", ""}
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty value",
+			declare: func(s *envconv.VarSet) {
+				*s.String("TEST_ENCODE", "app", "") = ""
+			},
+			want: "",
+		},
+		{
+			name: "empty value with EmptyAsUnset",
+			declare: func(s *envconv.VarSet) {
+				*s.String("TEST_ENCODE", "app", "", envconv.WithEmpty(envconv.EmptyAsUnset)) = ""
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty value with EmptyAsUnset equal to its default",
+			declare: func(s *envconv.VarSet) {
+				s.String("TEST_ENCODE", "", "", envconv.WithEmpty(envconv.EmptyAsUnset))
+			},
+			want: "",
+		},
+		{
+			name: "empty value of a required variable",
+			declare: func(s *envconv.VarSet) {
+				s.String("TEST_ENCODE", "", "", envconv.Required(), envconv.WithEmpty(envconv.EmptyAsUnset))
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty slice",
+			declare: func(s *envconv.VarSet) {
+				s.IntSlice("TEST_ENCODE", ",", nil, "")
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty slice with EmptyAsZero",
+			declare: func(s *envconv.VarSet) {
+				s.IntSlice("TEST_ENCODE", ",", nil, "", envconv.WithEmpty(envconv.EmptyAsZero))
+			},
+			want: "",
+		},
+		{
+			name: "NaN",
+			declare: func(s *envconv.VarSet) {
+				s.Float64("TEST_ENCODE", math.NaN(), "")
+			},
+			want: "NaN",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := envconv.NewVarSet("test")
+			tt.declare(s)
+			env, err := s.Encode(false)
+			if tt.wantErr {
+				assert.Error(t, err, "there should be an error")
+				assert.ErrorContains(t, err, "TEST_ENCODE", "the error should name the variable")
+				return
+			}
+			assert.NoError(t, err, "there should be no error")
+			assert.Equal(t, tt.want, env.Map()["TEST_ENCODE"], "they should be equal")
+		})
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	f := func(c config) bool {
+		in := envconv.NewVarSet("in")
+		set, _ := declareConfig(in)
+		set(c)
+		env, err := in.Encode(true)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		out := envconv.NewVarSet("out")
+		_, get := declareConfig(out)
+		if err := out.ParseFrom(env); err != nil {
+			t.Log(err)
+			return false
+		}
+		return assert.ObjectsAreEqual(normalizeConfig(c), normalizeConfig(get()))
+	}
+	assert.NoError(t, quick.Check(f, &quick.Config{MaxCount: 200}))
+}
+
+// normalizeConfig returns the passed config with empty slices set to
+// nil, as an empty slice is read back as nil.
+func normalizeConfig(c config) config {
+	if len(c.Shards) == 0 {
+		c.Shards = nil
+	}
+	if len(c.Hosts) == 0 {
+		c.Hosts = nil
+	}
+	return c
+}
+
+// encodeConfig is decoded and encoded by TestEncodeStruct, with every
+// field that could be empty using EmptyAsZero.
+type encodeConfig struct {
+	Port     int           `env:"PORT" default:"8080"`
+	Rate     float64       `env:"RATE"`
+	Debug    bool          `env:"DEBUG"`
+	Timeout  time.Duration `env:"TIMEOUT" default:"5s"`
+	Name     string        `env:"NAME" default:"app" empty:"zero"`
+	Limit    uint64        `env:"LIMIT"`
+	Shards   []int         `env:"SHARDS" separator:"," empty:"zero"`
+	Hosts    []string      `env:"HOSTS" separator:"," empty:"zero"`
+	Password string        `env:"PASSWORD" secret:"true"`
+	Ignored  string
+}
+
+func TestEncodeStruct(t *testing.T) {
+	cfg := encodeConfig{
+		Port:     9090,
+		Name:     "api",
+		Hosts:    []string{"a,b", "c"},
+		Password: "hunter2",
+		Ignored:  "ignored",
+	}
+
+	env, err := envconv.Encode(cfg, false, envconv.Prefix("APP_"), envconv.Quoted())
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, map[string]string{
+		"APP_PORT":    "9090",
+		"APP_RATE":    "0",
+		"APP_DEBUG":   "false",
+		"APP_TIMEOUT": "0s",
+		"APP_NAME":    "api",
+		"APP_LIMIT":   "0",
+		"APP_SHARDS":  "",
+		"APP_HOSTS":   `"a,b",c`,
+	}, env.Map(), "they should be equal")
+
+	env, err = envconv.Encode(&cfg, true, envconv.Prefix("APP_"), envconv.Quoted())
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "hunter2", env.Map()["APP_PASSWORD"], "they should be equal")
+
+	_, err = envconv.Encode(cfg, false, envconv.Prefix("APP_"))
+	assert.ErrorContains(t, err, "APP_HOSTS", "a value that is not read back unchanged should be an error")
+
+	_, err = envconv.Encode(42, false)
+	assert.Error(t, err, "there should be an error")
+}
+
+func TestEncodeStructMatrix(t *testing.T) {
+	type matrixConfig struct {
+		Matrix [][]int `env:"MATRIX" separator:";" inner_separator:","`
+	}
+
+	env, err := envconv.Encode(matrixConfig{Matrix: [][]int{{1, 2}, {3}}}, false)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "1,2;3", env.Map()["MATRIX"], "they should be equal")
+
+	var cfg matrixConfig
+	assert.NoError(t, envconv.Decode(&cfg, envconv.FromSource(env)), "there should be no error")
+	assert.Equal(t, [][]int{{1, 2}, {3}}, cfg.Matrix, "they should be equal")
+
+	_, err = envconv.Encode(matrixConfig{Matrix: [][]int{{1}, {}}}, false)
+	assert.ErrorContains(t, err, "empty nested slice", "an empty nested slice should be an error")
+}
+
+func TestEncodeStructRoundTrip(t *testing.T) {
+	opts := []envconv.Option{envconv.Prefix("APP_"), envconv.Quoted()}
+	f := func(in encodeConfig) bool {
+		in.Ignored = ""
+		env, err := envconv.Encode(in, true, opts...)
+		if err != nil {
+			t.Log(err)
+			return false
+		}
+
+		var out encodeConfig
+		if err := envconv.Decode(&out, append(opts, envconv.FromSource(env))...); err != nil {
+			t.Log(err)
+			return false
+		}
+		return assert.ObjectsAreEqual(normalizeEncodeConfig(in), normalizeEncodeConfig(out))
+	}
+	assert.NoError(t, quick.Check(f, &quick.Config{MaxCount: 200}))
+}
+
+// normalizeEncodeConfig returns the passed encodeConfig with empty
+// slices set to nil, as an empty slice is read back as nil.
+func normalizeEncodeConfig(c encodeConfig) encodeConfig {
+	if len(c.Shards) == 0 {
+		c.Shards = nil
+	}
+	if len(c.Hosts) == 0 {
+		c.Hosts = nil
+	}
+	return c
+}
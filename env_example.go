@@ -0,0 +1,115 @@
+package envconv
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteEnvExample writes a commented .env.example file describing every
+// declared variable to w. Variables declared from a struct with Struct
+// are grouped by the prefix of the struct, such as "APP_DB" for the
+// fields of a struct tagged prefix:"DB_" declared with the Prefix
+// option "APP_". Other variables are grouped by the part of their
+// name before the first underscore. Any variable with no prefix is
+// listed first, and each is preceded by a comment giving its usage,
+// type, default and whether it is required.
+//
+// If existing is not nil, it is read as a dotenv file, typically the
+// previous version of the output. Any value it assigns is written in
+// place of the default, and any variable it assigns that has not
+// been declared is kept at the end of the output, so re-running
+// WriteEnvExample never loses a value.
+func (s *VarSet) WriteEnvExample(w io.Writer, existing io.Reader) error {
+	values := map[string]string{}
+	var extra []DotenvEntry
+	if existing != nil {
+		entries, err := ParseDotenv(existing)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if s.Lookup(e.Name) == nil {
+				extra = append(extra, e)
+				continue
+			}
+			values[e.Name] = e.Value
+		}
+	}
+
+	var vars []*Var
+	s.VisitAll(func(v *Var) {
+		vars = append(vars, v)
+	})
+	sort.SliceStable(vars, func(i, j int) bool {
+		return envExampleGroup(vars[i]) < envExampleGroup(vars[j])
+	})
+
+	var b strings.Builder
+	group := ""
+	for _, v := range vars {
+		if g := envExampleGroup(v); g != group {
+			group = g
+			if b.Len() > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "# %s\n\n", group)
+		}
+
+		if v.Usage != "" {
+			fmt.Fprintf(&b, "# %s\n", strings.ReplaceAll(v.Usage, "\n", "\n# "))
+		}
+		details := []string{v.Type}
+		switch {
+		case v.Required:
+			details = append(details, "required")
+		case v.Secret && v.DefValue != "":
+			details = append(details, "default "+v.DefValue)
+		default:
+			details = append(details, fmt.Sprintf("default %q", v.DefValue))
+		}
+		if v.Secret {
+			details = append(details, "secret")
+		}
+		fmt.Fprintf(&b, "# (%s)\n", strings.Join(details, ", "))
+
+		value, ok := values[v.Name]
+		if !ok && !v.Required && !v.Secret {
+			value = v.DefValue
+		}
+		fmt.Fprintf(&b, "%s=%s\n", v.Name, formatDotenvValue(value))
+	}
+
+	if len(extra) > 0 {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("# Not declared\n\n")
+		for _, e := range extra {
+			fmt.Fprintf(&b, "%s=%s\n", e.Name, formatDotenvValue(e.Value))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteEnvExample writes a commented .env.example file describing
+// every variable declared in the default VarSet to w.
+func WriteEnvExample(w io.Writer, existing io.Reader) error {
+	return Variables.WriteEnvExample(w, existing)
+}
+
+// envExampleGroup returns the group heading used for the passed
+// variable in a .env.example file, or an empty string if the
+// variable has no prefix to group it by.
+func envExampleGroup(v *Var) string {
+	if v.group != "" {
+		return v.group
+	}
+	if prefix, _, ok := strings.Cut(v.Name, "_"); ok {
+		return prefix
+	}
+	return ""
+}
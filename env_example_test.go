@@ -0,0 +1,122 @@
+package envconv_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// newEnvExampleVarSet returns a VarSet declaring a representative mix of
+// variables for the .env.example tests.
+func newEnvExampleVarSet() *envconv.VarSet {
+	s := envconv.NewVarSet("test")
+	s.String("DATABASE_HOST", "localhost", "database host")
+	s.Int("DATABASE_PORT", 5432, "database port")
+	s.String("DATABASE_PASSWORD", "", "database password", envconv.Required(), envconv.Redact())
+	s.Duration("HTTP_TIMEOUT", 5*time.Second, "request timeout")
+	s.StringSlice("HTTP_ORIGINS", ",", []string{"a b", "c"}, "allowed origins")
+	s.Bool("DEBUG", false, "enable debug output")
+	return s
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, newEnvExampleVarSet().WriteEnvExample(&b, nil), "there should be no error")
+
+	assert.Equal(t, `# enable debug output
+# (bool, default "false")
+DEBUG=false
+
+# DATABASE
+
+# database host
+# (string, default "localhost")
+DATABASE_HOST=localhost
+# database password
+# (string, required, secret)
+DATABASE_PASSWORD=
+# database port
+# (int, default "5432")
+DATABASE_PORT=5432
+
+# HTTP
+
+# allowed origins
+# ([]string, default "a b,c")
+HTTP_ORIGINS="a b,c"
+# request timeout
+# (time.Duration, default "5s")
+HTTP_TIMEOUT=5s
+`, b.String(), "they should be equal")
+}
+
+func TestWriteEnvExamplePreservesValues(t *testing.T) {
+	existing := `# edited by hand
+DATABASE_HOST=db.internal
+export DATABASE_PASSWORD='s3cr3t #1'
+HTTP_TIMEOUT=30s # slower upstream
+LEGACY_FLAG="on \"yes\""
+`
+
+	var b bytes.Buffer
+	assert.NoError(t, newEnvExampleVarSet().WriteEnvExample(&b, strings.NewReader(existing)), "there should be no error")
+	output := b.String()
+
+	assert.Contains(t, output, "DATABASE_HOST=db.internal\n")
+	assert.Contains(t, output, "DATABASE_PASSWORD=\"s3cr3t #1\"\n")
+	assert.Contains(t, output, "DATABASE_PORT=5432\n")
+	assert.Contains(t, output, "HTTP_TIMEOUT=30s\n")
+	assert.Contains(t, output, "# Not declared\n\nLEGACY_FLAG=\"on \\\"yes\\\"\"\n")
+
+	t.Run("re-running is stable", func(t *testing.T) {
+		var again bytes.Buffer
+		assert.NoError(t, newEnvExampleVarSet().WriteEnvExample(&again, strings.NewReader(output)), "there should be no error")
+		assert.Equal(t, output, again.String(), "they should be equal")
+	})
+
+}
+
+func TestWriteEnvExampleInvalidExisting(t *testing.T) {
+	var b bytes.Buffer
+	err := newEnvExampleVarSet().WriteEnvExample(&b, strings.NewReader("NOT AN ASSIGNMENT\n"))
+	assert.Error(t, err, "there should be an error")
+	assert.Contains(t, err.Error(), "line 1")
+}
+
+func TestWriteEnvExampleStruct(t *testing.T) {
+	var cfg struct {
+		Port int `env:"PORT" default:"8080" usage:"port to listen on"`
+		DB   struct {
+			Host     string `env:"HOST" default:"localhost"`
+			Password string `env:"PASSWORD" required:"true" secret:"true"`
+		} `prefix:"DB_"`
+		Cache struct {
+			TTL time.Duration `env:"CACHE_TTL" default:"1m"`
+		}
+	}
+
+	s := envconv.NewVarSet("test")
+	assert.NoError(t, s.Struct(&cfg, envconv.Prefix("APP_")), "there should be no error")
+
+	var b bytes.Buffer
+	assert.NoError(t, s.WriteEnvExample(&b, nil), "there should be no error")
+	assert.Equal(t, `# APP
+
+# (time.Duration, default "1m0s")
+APP_CACHE_TTL=1m0s
+# port to listen on
+# (int, default "8080")
+APP_PORT=8080
+
+# APP_DB
+
+# (string, default "localhost")
+APP_DB_HOST=localhost
+# (string, required, secret)
+APP_DB_PASSWORD=
+`, b.String(), "they should be equal")
+}
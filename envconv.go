@@ -15,36 +15,294 @@
 // int16, int32, int63, uint, uint8, uint16, uint32, uint64,
 // float32, float64, bool, byte, string and time.Duration.
 //
-// You can also convert to a slice of any of the available types.
+// You can also convert to a slice of any of the available types, and
+// to a nested slice of any of them other than byte using an outer
+// and an inner separator. Structured values can be decoded from
+// JSON into any type with ToJSON, and sensitive values can be
+// loaded as a self-redacting Secret with ToSecret. A whole
+// configuration struct can be filled in at once with Decode,
+// configured by struct tags on its fields.
+//
+// Every function accepts optional trailing Options, which adjust
+// how that single call loads and converts its variable. For
+// example, WithEmpty controls how a variable that is set
+// to an empty string is treated, and FromSource loads
+// the variable from a Source, such as a dotenv file or
+// a Snapshot, rather than from the process environment.
+//
+// All of the slice functions split their value in the same way. By
+// default, leading and trailing whitespace is trimmed from the value
+// and from every element, so "1s, 2s" and "1s,2s" are equivalent.
+// Empty elements are kept and passed on to the conversion, and a
+// trailing separator produces a final empty element. The Strict,
+// DropEmpty and RejectTrailingSeparator options change this, and
+// the Quoted option allows elements to contain the separator.
+// Join formats a slice using the same quoting rules.
 package envconv
 
 import (
 	"errors"
+	"fmt"
 	"os"
+	"strings"
 )
 
-// LoadFromEvironment returns the value of the requested environment variable.
-// An error is returned  if that variable is not set or (assuming the
-// allowEmpty parameter is set to false), the loaded environment
-// variable  is empty.
-func loadFromEnvironment(varName string, allowEmpty bool) (string, error) {
-	val, ok := os.LookupEnv(varName)
+var (
+	// ErrNotSet is returned, wrapped in a NotSetError, when the
+	// requested environment variable is not set.
+	ErrNotSet = errors.New("unknown environment variable")
+
+	// ErrEmpty is returned when the requested environment variable is
+	// set to an empty string and the EmptyAsError policy is in use.
+	ErrEmpty = errors.New("empty environment variable")
+
+	// ErrConflict is returned when the requested environment variable
+	// and one of its aliases are both set, to different values.
+	ErrConflict = errors.New("conflicting environment variables")
+)
+
+// LoadFromEvironment returns the value of the requested environment variable,
+// or of the first of its aliases that is set. An error is returned if none of
+// those variables are set, if they are set to conflicting values or,
+// depending on the empty policy in the passed options, the loaded
+// environment variable is empty.
+func loadFromEnvironment(varName string, o *options) (string, error) {
+	name, val, ok := varName, "", false
+	for _, n := range append([]string{varName}, o.aliases...) {
+		v, found := lookupEnv(n, o)
+		if !found {
+			continue
+		}
+		if !ok {
+			name, val, ok = n, v, true
+		} else if v != val {
+			err := fmt.Errorf("%s: %w: %s and %s differ", varName, ErrConflict, name, n)
+			record(varName, OriginNone, "", "", err, o)
+			return "", err
+		}
+	}
+
+	origin := OriginEnvironment
+	if o.source != nil && o.source != Environment {
+		origin = OriginSource
+	}
+	from := name
+	if !ok && o.fileSecrets {
+		var err error
+		name, val, ok, err = loadFromFile(varName, o)
+		from = name + fileSuffix
+		if err != nil {
+			record(varName, OriginNone, from, "", err, o)
+			return "", err
+		}
+		origin = OriginFile
+	}
+
 	if !ok {
-		return "", errors.New("unknown environment variable")
+		err := notSet(varName, o)
+		record(varName, OriginNone, "", "", err, o)
+		return "", err
+	}
+	if val == "" && o.empty == EmptyAsError {
+		err := fmt.Errorf("%s: %w", varName, ErrEmpty)
+		record(varName, OriginNone, from, "", err, o)
+		return "", err
 	}
-	if !allowEmpty && val == "" {
-		return "", errors.New("empty environment variable")
+	if name != varName && o.onDeprecated != nil {
+		o.onDeprecated(name, varName)
 	}
+	record(varName, origin, from, val, nil, o)
 	return val, nil
 }
 
-// LoadFromEvironmentWithDefault returns the value of the requested environment variable.
-// A default value is returned if that variable is not set or the loaded environment
-// variable  is empty.
-func loadFromEnvironmentWithDefault(varName string, defaultValue string) string {
-	val, err := loadFromEnvironment(varName, true)
+// fileSuffix is added to the name of a variable, or of one of its
+// aliases, to find the file read with the FileSecrets option.
+const fileSuffix = "_FILE"
+
+// loadFromFile reads the value of the requested environment variable
+// from the file named by the first of its names that is set with a
+// "_FILE" suffix. It returns that name, without the suffix, the
+// contents of the file without a trailing newline, and whether
+// one was set. An empty file is treated as not set with the
+// EmptyAsUnset policy, as an empty variable is.
+func loadFromFile(varName string, o *options) (string, string, bool, error) {
+	for _, n := range append([]string{varName}, o.aliases...) {
+		path, found := lookupEnv(n+fileSuffix, o)
+		if !found {
+			continue
+		}
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return n, "", false, fmt.Errorf("%s: %w", varName, err)
+		}
+		val := strings.TrimSuffix(strings.TrimSuffix(string(contents), "\n"), "\r")
+		if val == "" && o.empty == EmptyAsUnset {
+			return n, "", false, nil
+		}
+		return n, val, true, nil
+	}
+	return varName, "", false, nil
+}
+
+// lookupEnv returns the value of the named environment variable and
+// whether it is set, from the Source in the passed options or from
+// the process environment. A variable set to an empty string is
+// reported as not set when the EmptyAsUnset policy is in use.
+func lookupEnv(varName string, o *options) (string, bool) {
+	src := o.source
+	if src == nil {
+		src = Environment
+	}
+	val, ok := src.Lookup(varName)
+	if ok && val == "" && o.empty == EmptyAsUnset {
+		return "", false
+	}
+	return val, ok
+}
+
+// convert returns the value of the requested environment variable
+// converted to type T by the passed conversion function. An error
+// will be returned if the environment variable is not found or
+// the conversion to type T fails.
+func convert[T any](varName string, opts []Option, conversionFunc func(string) (T, error)) (T, error) {
+	return convertAs(varName, typeName[T](), opts, conversionFunc)
+}
+
+// convertAs is convert, naming the type converted to typ in any
+// ConversionError, for values whose type is only known at run
+// time, such as struct fields decoded as JSON.
+func convertAs[T any](varName string, typ string, opts []Option, conversionFunc func(string) (T, error)) (T, error) {
+	var zero T
+	o := newOptions(opts)
+	value, err := loadFromEnvironment(varName, o)
+	if err != nil {
+		return zero, err
+	}
+	if value == "" && o.empty == EmptyAsZero {
+		record(varName, OriginZero, "", "", nil, o)
+		return zero, nil
+	}
+
+	convertedValue, err := conversionFunc(value)
+	if err != nil {
+		value, err = redact(value, err, o)
+		err = &ConversionError{VarName: varName, Value: value, Type: typ, Err: err}
+		recordError(varName, err, o)
+		return zero, err
+	}
+	return convertedValue, nil
+}
+
+// convertSlice returns the value of the requested environment variable
+// split by the passed separator, with each element converted to type
+// T by the passed conversion function. An error will be returned if
+// the environment variable is not found, the value cannot be split
+// or the conversion of any element fails.
+func convertSlice[T any](varName string, separator string, opts []Option, conversionFunc func(string) (T, error)) ([]T, error) {
+	o := newOptions(opts)
+	value, err := loadFromEnvironment(varName, o)
+	if err != nil {
+		return []T{}, err
+	}
+	if value == "" && o.empty == EmptyAsZero {
+		record(varName, OriginZero, "", "", nil, o)
+		return []T{}, nil
+	}
+
+	elements, err := splitValue(value, separator, o)
+	if err != nil {
+		recordError(varName, err, o)
+		return []T{}, err
+	}
+
+	var convertedValues = []T{}
+	var elementErrors ElementErrors
+	for i, v := range elements {
+		convertedValue, err := conversionFunc(v)
+		if err != nil {
+			v, err = redact(v, err, o)
+			err = &ElementError{VarName: varName, Index: i, Element: v, Err: err}
+			if !o.lenient {
+				recordError(varName, err, o)
+				return []T{}, err
+			}
+			elementErrors = append(elementErrors, err)
+			continue
+		}
+		convertedValues = append(convertedValues, convertedValue)
+	}
+	if elementErrors != nil {
+		recordError(varName, elementErrors, o)
+		return convertedValues, elementErrors
+	}
+	return convertedValues, nil
+}
+
+// convertSlice2D returns the value of the requested environment variable
+// split by the outer separator, with each resulting element split
+// again by the inner separator and converted to type T by the
+// passed conversion function. An error will be returned if the
+// environment variable is not found, the value cannot be split
+// or the conversion of any element fails.
+func convertSlice2D[T any](varName string, outerSeparator string, innerSeparator string, opts []Option, conversionFunc func(string) (T, error)) ([][]T, error) {
+	o := newOptions(opts)
+	value, err := loadFromEnvironment(varName, o)
 	if err != nil {
+		return [][]T{}, err
+	}
+	if value == "" && o.empty == EmptyAsZero {
+		record(varName, OriginZero, "", "", nil, o)
+		return [][]T{}, nil
+	}
+
+	rows, err := splitRaw(value, outerSeparator, o)
+	if err != nil {
+		recordError(varName, err, o)
+		return [][]T{}, err
+	}
+
+	var convertedRows = [][]T{}
+	var elementErrors ElementErrors
+	for i, row := range rows {
+		elements, err := splitValue(row, innerSeparator, o)
+		if err != nil {
+			recordError(varName, err, o)
+			return [][]T{}, err
+		}
+
+		var convertedValues = []T{}
+		for j, v := range elements {
+			convertedValue, err := conversionFunc(v)
+			if err != nil {
+				v, err = redact(v, err, o)
+				err = &NestedElementError{VarName: varName, Outer: i, Inner: j, Element: v, Err: err}
+				if !o.lenient {
+					recordError(varName, err, o)
+					return [][]T{}, err
+				}
+				elementErrors = append(elementErrors, err)
+				continue
+			}
+			convertedValues = append(convertedValues, convertedValue)
+		}
+		convertedRows = append(convertedRows, convertedValues)
+	}
+	if elementErrors != nil {
+		recordError(varName, elementErrors, o)
+		return convertedRows, elementErrors
+	}
+	return convertedRows, nil
+}
+
+// withDefault returns the passed value, or the passed default
+// value if err is not nil. The partially converted value is
+// kept when err only reports the elements skipped by the
+// Lenient option.
+func withDefault[T any](varName string, value T, err error, defaultValue T, opts []Option) T {
+	var elementErrors ElementErrors
+	if err != nil && !errors.As(err, &elementErrors) {
+		record(varName, OriginDefault, "", fmt.Sprint(defaultValue), err, newOptions(opts))
 		return defaultValue
 	}
-	return val
+	return value
 }
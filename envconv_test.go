@@ -1,9 +1,9 @@
 package envconv_test
 
 import (
-	"os"
 	"testing"
 
+	"github.com/rmhubbert/envconv"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -15,7 +15,7 @@ type testReturnValueType interface {
 
 // runTest provides a generic test run for the convertor function types that
 // take a single string and return a T and an error
-func runTest[T testReturnValueType, F func(string) (T, error)](
+func runTest[T testReturnValueType, F func(string, ...envconv.Option) (T, error)](
 	t *testing.T,
 	env string,
 	value string,
@@ -24,7 +24,7 @@ func runTest[T testReturnValueType, F func(string) (T, error)](
 	handler F,
 ) {
 	t.Run(env, func(t *testing.T) {
-		os.Setenv(env, value)
+		t.Setenv(env, value)
 		v, err := handler(env)
 		if errorExpected {
 			assert.Error(t, err, "there should be an error")
@@ -37,7 +37,7 @@ func runTest[T testReturnValueType, F func(string) (T, error)](
 
 // runEmptyTest provides a generic test run for testing an empty environment
 // variable on convertor function types that retun an error.
-func runEmptyTest[T testReturnValueType, F func(string) (T, error)](t *testing.T, expected T, handler F) {
+func runEmptyTest[T testReturnValueType, F func(string, ...envconv.Option) (T, error)](t *testing.T, expected T, handler F) {
 	t.Run("TEST_NON_EXISTANT does not exist", func(t *testing.T) {
 		v, err := handler("TEST_NON_EXISTANT")
 		assert.Error(t, err, "there should be an error")
@@ -47,7 +47,7 @@ func runEmptyTest[T testReturnValueType, F func(string) (T, error)](t *testing.T
 
 // runWithDefaultTest provides a generic test run for the convertor function
 // types that return a default value.
-func runWithDefaultTest[T testReturnValueType, F func(string, T) T](
+func runWithDefaultTest[T testReturnValueType, F func(string, T, ...envconv.Option) T](
 	t *testing.T,
 	env string,
 	value string,
@@ -56,7 +56,7 @@ func runWithDefaultTest[T testReturnValueType, F func(string, T) T](
 	handler F,
 ) {
 	t.Run(env, func(t *testing.T) {
-		os.Setenv(env, value)
+		t.Setenv(env, value)
 		v := handler(env, defaultValue)
 		if v != expected {
 			assert.Equal(t, defaultValue, v, "they should be equal")
@@ -69,7 +69,7 @@ func runWithDefaultTest[T testReturnValueType, F func(string, T) T](
 // runWithDefaultEmptyTest provides a generic test run for testing an empty
 // environment variable on convertor function types that retun a default
 // value.
-func runWithDefaultEmptyTest[T testReturnValueType, F func(string, T) T](t *testing.T, expected T, handler F) {
+func runWithDefaultEmptyTest[T testReturnValueType, F func(string, T, ...envconv.Option) T](t *testing.T, expected T, handler F) {
 	t.Run("TEST_NON_EXISTANT does not exist", func(t *testing.T) {
 		v := handler("TEST_NON_EXISTANT", expected)
 		assert.Equal(t, expected, v, "they should be equal")
@@ -78,7 +78,7 @@ func runWithDefaultEmptyTest[T testReturnValueType, F func(string, T) T](t *test
 
 // runWithDefaultTest provides a generic test run for the convertor function
 // types that return a default value.
-func runSliceTest[T testReturnValueType, F func(string, string) ([]T, error)](
+func runSliceTest[T testReturnValueType, F func(string, string, ...envconv.Option) ([]T, error)](
 	t *testing.T,
 	env string,
 	value string,
@@ -88,7 +88,7 @@ func runSliceTest[T testReturnValueType, F func(string, string) ([]T, error)](
 	handler F,
 ) {
 	t.Run(env, func(t *testing.T) {
-		os.Setenv(env, value)
+		t.Setenv(env, value)
 		v, err := handler(env, separator)
 		if errorExpected {
 			assert.Error(t, err, "there should be an error")
@@ -101,7 +101,7 @@ func runSliceTest[T testReturnValueType, F func(string, string) ([]T, error)](
 
 // runSliceEmptyTest provides a generic test run for testing an empty environment
 // variable on convertor function types that return a an error.
-func runSliceEmptyTest[T testReturnValueType, F func(string, string) ([]T, error)](t *testing.T, separator string, expected []T, handler F) {
+func runSliceEmptyTest[T testReturnValueType, F func(string, string, ...envconv.Option) ([]T, error)](t *testing.T, separator string, expected []T, handler F) {
 	t.Run("TEST_NON_EXISTANT does not exist", func(t *testing.T) {
 		v, err := handler("TEST_NON_EXISTANT", separator)
 		assert.Error(t, err, "there should be an error")
@@ -111,7 +111,7 @@ func runSliceEmptyTest[T testReturnValueType, F func(string, string) ([]T, error
 
 // runSliceWithDefaultTest provides a generic test run for the convertor function
 // types that return a default value.
-func runSliceWithDefaultTest[T testReturnValueType, F func(string, string, []T) []T](
+func runSliceWithDefaultTest[T testReturnValueType, F func(string, string, []T, ...envconv.Option) []T](
 	t *testing.T,
 	env string,
 	value string,
@@ -122,7 +122,7 @@ func runSliceWithDefaultTest[T testReturnValueType, F func(string, string, []T)
 	handler F,
 ) {
 	t.Run(env, func(t *testing.T) {
-		os.Setenv(env, value)
+		t.Setenv(env, value)
 		v := handler(env, separator, defaultValue)
 		if defaultExpected {
 			assert.Equal(t, defaultValue, v, "they should be equal")
@@ -135,7 +135,7 @@ func runSliceWithDefaultTest[T testReturnValueType, F func(string, string, []T)
 // runSliceWithDefaultEmptyTest provides a generic test run for testing an empty
 // environment variable on convertor function types that retun a default
 // value.
-func runSliceWithDefaultEmptyTest[T testReturnValueType, F func(string, string, []T) []T](
+func runSliceWithDefaultEmptyTest[T testReturnValueType, F func(string, string, []T, ...envconv.Option) []T](
 	t *testing.T,
 	separator string,
 	expected []T,
@@ -146,3 +146,25 @@ func runSliceWithDefaultEmptyTest[T testReturnValueType, F func(string, string,
 		assert.Equal(t, expected, v, "they should be equal")
 	})
 }
+
+// runSlice2DTest provides a generic test run for the nested slice convertor
+// function types that take an outer and an inner separator.
+func runSlice2DTest[T testReturnValueType | string, F func(string, string, string, ...envconv.Option) ([][]T, error)](
+	t *testing.T,
+	env string,
+	value string,
+	expected [][]T,
+	errorExpected bool,
+	handler F,
+) {
+	t.Run(env, func(t *testing.T) {
+		t.Setenv(env, value)
+		v, err := handler(env, ";", ",")
+		if errorExpected {
+			assert.Error(t, err, "there should be an error")
+		} else {
+			assert.NoError(t, err, "there should be no error")
+		}
+		assert.Equal(t, expected, v, "they should be equal")
+	})
+}
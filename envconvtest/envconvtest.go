@@ -0,0 +1,144 @@
+// Package envconvtest provides helpers for testing code that reads
+// its configuration with the envconv package.
+//
+// Tests that call os.Setenv change the environment of the whole
+// process, which leaks into other tests and rules out t.Parallel.
+// Where possible, build an in-memory Source with Source or Dotenv
+// and pass it to the code under test with envconv.FromSource.
+// Where the process environment must be used, Setenv and
+// Unsetenv restore it when the test ends.
+package envconvtest
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+)
+
+// Source returns an in-memory Source holding a copy of the passed
+// variables. It can be changed with Set and Unset without
+// affecting the process environment or other tests.
+func Source(values map[string]string) *envconv.MutableEnv {
+	return envconv.NewMutableEnv(values)
+}
+
+// Dotenv returns an in-memory Source holding the variables assigned
+// in the passed dotenv text, as parsed by envconv.ParseDotenv.
+// The test fails immediately if the text cannot be parsed.
+func Dotenv(tb testing.TB, text string) *envconv.MutableEnv {
+	tb.Helper()
+	entries, err := envconv.ParseDotenv(strings.NewReader(text))
+	if err != nil {
+		tb.Fatalf("envconvtest: %v", err)
+	}
+	values := make(map[string]string, len(entries))
+	for _, e := range entries {
+		values[e.Name] = e.Value
+	}
+	return envconv.NewMutableEnv(values)
+}
+
+// Setenv sets each of the passed environment variables for the rest
+// of the test, restoring their previous values, or unsetting them,
+// when it ends. Like testing.T.Setenv, it cannot be used in
+// parallel tests.
+func Setenv(tb testing.TB, values map[string]string) {
+	tb.Helper()
+	for name, value := range values {
+		tb.Setenv(name, value)
+	}
+}
+
+// Unsetenv unsets each of the named environment variables for the
+// rest of the test, restoring any previous values when it ends.
+// Like testing.T.Setenv, it cannot be used in parallel tests.
+func Unsetenv(tb testing.TB, names ...string) {
+	tb.Helper()
+	for _, name := range names {
+		// Setenv registers the restore, and rejects parallel tests.
+		tb.Setenv(name, "")
+		os.Unsetenv(name)
+	}
+}
+
+// AssertNotSet checks that err reports that the named variable is
+// not set, failing the test if it does not. The error may be one
+// of several joined together, as returned by envconv.Decode or
+// VarSet.Parse. It returns whether the check passed.
+func AssertNotSet(tb testing.TB, err error, varName string) bool {
+	tb.Helper()
+	matched := findError(err, func(err error) bool {
+		notSet, ok := err.(*envconv.NotSetError)
+		return ok && notSet.VarName == varName
+	})
+	if !matched {
+		tb.Errorf("envconvtest: expected %s to be reported as not set, got: %v", varName, err)
+		return false
+	}
+	return true
+}
+
+// AssertConversionError checks that err is, or wraps, a
+// ConversionError for the named variable and raw value, failing
+// the test if it is not. As with AssertNotSet, the error may be
+// one of several joined together. It returns whether the
+// check passed.
+func AssertConversionError(tb testing.TB, err error, varName string, value string) bool {
+	tb.Helper()
+	matched := findError(err, func(err error) bool {
+		conversionError, ok := err.(*envconv.ConversionError)
+		return ok && conversionError.VarName == varName && conversionError.Value == value
+	})
+	if !matched {
+		tb.Errorf("envconvtest: expected a conversion error for %s=%q, got: %v", varName, value, err)
+		return false
+	}
+	return true
+}
+
+// AssertElementError checks that err is, or wraps, an ElementError
+// for the element of the named slice variable at the passed index,
+// failing the test if it is not. With the Lenient option, any of
+// the collected errors may match. It returns whether the check
+// passed.
+func AssertElementError(tb testing.TB, err error, varName string, index int) bool {
+	tb.Helper()
+	if !matchElementError(err, varName, index) {
+		tb.Errorf("envconvtest: expected an error for element %d of %s, got: %v", index, varName, err)
+		return false
+	}
+	return true
+}
+
+// matchElementError reports whether err is, or wraps, an ElementError
+// for the passed variable and index.
+func matchElementError(err error, varName string, index int) bool {
+	return findError(err, func(err error) bool {
+		elementError, ok := err.(*envconv.ElementError)
+		return ok && elementError.VarName == varName && elementError.Index == index
+	})
+}
+
+// findError reports whether err, or any error in the tree that it
+// wraps, including each of several joined errors, satisfies match.
+func findError(err error, match func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if match(err) {
+		return true
+	}
+	switch e := err.(type) {
+	case interface{ Unwrap() error }:
+		return findError(e.Unwrap(), match)
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			if findError(err, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,126 @@
+package envconvtest_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/rmhubbert/envconv/envconvtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder is a testing.TB that records failures rather than
+// reporting them, so that the assertions can be tested.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+	src := envconvtest.Source(map[string]string{"PORT": "9090"})
+
+	port, err := envconv.ToInt("PORT", envconv.FromSource(src))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 9090, port, "they should be equal")
+
+	src.Unset("PORT")
+	_, err = envconv.ToInt("PORT", envconv.FromSource(src))
+	envconvtest.AssertNotSet(t, err, "PORT")
+}
+
+func TestDotenv(t *testing.T) {
+	t.Parallel()
+	src := envconvtest.Dotenv(t, `
+# worker settings
+export RATE=10
+HOSTS="a, b"
+`)
+
+	hosts, err := envconv.ToStringSlice("HOSTS", ",", envconv.FromSource(src))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, []string{"a", "b"}, hosts, "they should be equal")
+	assert.Equal(t, []string{"HOSTS=a, b", "RATE=10"}, src.Environ(), "they should be equal")
+}
+
+func TestSetenv(t *testing.T) {
+	os.Setenv("TEST_ENVCONVTEST_KEPT", "before")
+	os.Unsetenv("TEST_ENVCONVTEST_ADDED")
+	os.Setenv("TEST_ENVCONVTEST_REMOVED", "before")
+
+	t.Run("scoped", func(t *testing.T) {
+		envconvtest.Setenv(t, map[string]string{
+			"TEST_ENVCONVTEST_KEPT":  "during",
+			"TEST_ENVCONVTEST_ADDED": "during",
+		})
+		envconvtest.Unsetenv(t, "TEST_ENVCONVTEST_REMOVED")
+
+		assert.Equal(t, "during", os.Getenv("TEST_ENVCONVTEST_KEPT"), "they should be equal")
+		assert.Equal(t, "during", os.Getenv("TEST_ENVCONVTEST_ADDED"), "they should be equal")
+		_, ok := os.LookupEnv("TEST_ENVCONVTEST_REMOVED")
+		assert.False(t, ok, "it should not be set")
+	})
+
+	assert.Equal(t, "before", os.Getenv("TEST_ENVCONVTEST_KEPT"), "they should be equal")
+	_, ok := os.LookupEnv("TEST_ENVCONVTEST_ADDED")
+	assert.False(t, ok, "it should not be set")
+	assert.Equal(t, "before", os.Getenv("TEST_ENVCONVTEST_REMOVED"), "they should be equal")
+}
+
+func TestAssertions(t *testing.T) {
+	t.Parallel()
+	src := envconv.FromSource(envconvtest.Source(map[string]string{
+		"PORT":  "eighty",
+		"PORTS": "80,eighty,443,ninety",
+	}))
+
+	_, notSet := envconv.ToInt("MISSING", src)
+	_, conversion := envconv.ToInt("PORT", src)
+	_, element := envconv.ToIntSlice("PORTS", ",", src)
+	_, lenient := envconv.ToIntSlice("PORTS", ",", src, envconv.Lenient())
+
+	var cfg struct {
+		First  string `env:"REVA" required:"true"`
+		Second string `env:"REVB" required:"true"`
+		Port   int    `env:"PORT"`
+		Ports  []int  `env:"PORTS" separator:","`
+	}
+	decode := envconv.Decode(&cfg, src)
+
+	testData := []struct {
+		name   string
+		assert func(testing.TB) bool
+		passed bool
+	}{
+		{"not set", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, notSet, "MISSING") }, true},
+		{"not set, other name", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, notSet, "PORT") }, false},
+		{"not set, other error", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, conversion, "PORT") }, false},
+		{"conversion", func(tb testing.TB) bool { return envconvtest.AssertConversionError(tb, conversion, "PORT", "eighty") }, true},
+		{"conversion, other value", func(tb testing.TB) bool { return envconvtest.AssertConversionError(tb, conversion, "PORT", "80") }, false},
+		{"conversion, no error", func(tb testing.TB) bool { return envconvtest.AssertConversionError(tb, nil, "PORT", "80") }, false},
+		{"element", func(tb testing.TB) bool { return envconvtest.AssertElementError(tb, element, "PORTS", 1) }, true},
+		{"element, other index", func(tb testing.TB) bool { return envconvtest.AssertElementError(tb, element, "PORTS", 3) }, false},
+		{"lenient element", func(tb testing.TB) bool { return envconvtest.AssertElementError(tb, lenient, "PORTS", 3) }, true},
+		{"decode, first not set", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, decode, "REVA") }, true},
+		{"decode, second not set", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, decode, "REVB") }, true},
+		{"decode, set", func(tb testing.TB) bool { return envconvtest.AssertNotSet(tb, decode, "PORT") }, false},
+		{"decode, conversion", func(tb testing.TB) bool { return envconvtest.AssertConversionError(tb, decode, "PORT", "eighty") }, true},
+		{"decode, other value", func(tb testing.TB) bool { return envconvtest.AssertConversionError(tb, decode, "PORT", "80") }, false},
+		{"decode, element", func(tb testing.TB) bool { return envconvtest.AssertElementError(tb, decode, "PORTS", 1) }, true},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			assert.Equal(t, td.passed, td.assert(r), "they should be equal")
+			assert.Equal(t, !td.passed, len(r.failures) == 1, "there should be a failure if the check did not pass")
+		})
+	}
+}
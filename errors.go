@@ -0,0 +1,104 @@
+package envconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// redacted replaces the raw value of an environment variable in any
+// error produced while the Redact option is in use.
+const redacted = "[REDACTED]"
+
+// ConversionError is returned when the value of the requested
+// environment variable cannot be converted to the requested
+// type. Value holds the raw value, unless the Redact
+// option was used.
+type ConversionError struct {
+	VarName string
+	Value   string
+	Type    string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *ConversionError) Error() string {
+	return fmt.Sprintf("%s: cannot convert %s to %s: %v", e.VarName, quoteValue(e.Value), e.Type, e.Err)
+}
+
+// quoteValue quotes the passed raw value for an error message, unless
+// it has been redacted, so that the placeholder reads the same in
+// every error.
+func quoteValue(value string) string {
+	if value == redacted {
+		return value
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// redactedError replaces the message of the error it wraps with one
+// that holds no part of the raw value, while leaving the error
+// chain intact.
+type redactedError struct {
+	message string
+	err     error
+}
+
+// Error implements the error interface.
+func (e *redactedError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the original error.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redact returns the passed raw value and error unchanged, unless the
+// Redact option is in use. In that case the value is replaced and
+// the error is wrapped so that its message never contains any
+// part of the raw value.
+func redact(value string, err error, o *options) (string, error) {
+	if !o.redact {
+		return value, err
+	}
+	if value == "" {
+		return redacted, err
+	}
+	return redacted, &redactedError{
+		message: redactedMessage(err),
+		err:     err,
+	}
+}
+
+// redactedMessage describes the passed conversion error by the kind of
+// failure alone, such as "strconv.ParseInt: invalid syntax". It is
+// built from fields that never hold the value, rather than by
+// editing the original message, which may quote the value,
+// or any part of it, in any form.
+func redactedMessage(err error) string {
+	var numErr *strconv.NumError
+	var jsonErr *JSONError
+	switch {
+	case errors.As(err, &numErr):
+		return "strconv." + numErr.Func + ": " + numErr.Err.Error()
+	case errors.As(err, &jsonErr):
+		if jsonErr.Offset < 0 {
+			return "invalid JSON"
+		}
+		return fmt.Sprintf("invalid JSON at offset %d", jsonErr.Offset)
+	}
+	return "invalid value"
+}
+
+// typeName returns the name of type T, as used in error messages.
+// Unlike the %T verb, it names interface types too.
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
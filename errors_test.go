@@ -0,0 +1,83 @@
+package envconv_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionError(t *testing.T) {
+	os.Setenv("TEST_CONVERSION_ERROR", "notanumber")
+
+	_, err := envconv.ToUint8("TEST_CONVERSION_ERROR")
+	var conversionErr *envconv.ConversionError
+	if assert.ErrorAs(t, err, &conversionErr) {
+		assert.Equal(t, "TEST_CONVERSION_ERROR", conversionErr.VarName, "they should be equal")
+		assert.Equal(t, "notanumber", conversionErr.Value, "they should be equal")
+		assert.Equal(t, "uint8", conversionErr.Type, "they should be equal")
+	}
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestRedact(t *testing.T) {
+	os.Setenv("TEST_REDACT", "hunter2")
+	os.Setenv("TEST_REDACT_SLICE", "1,hunter2")
+
+	_, err := envconv.ToDuration("TEST_REDACT", envconv.Redact())
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.Contains(t, err.Error(), "[REDACTED]")
+
+	_, err = envconv.ToIntSlice("TEST_REDACT_SLICE", ",", envconv.Redact())
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	_, err = envconv.ToJSON[int]("TEST_REDACT", envconv.Redact())
+	assert.NotContains(t, err.Error(), "hunter2")
+	assert.NotContains(t, err.Error(), "'h'", "no part of the value should be quoted")
+}
+
+func TestRedactMessage(t *testing.T) {
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{
+		"TEST_REDACT_SHORT":    "t",
+		"TEST_REDACT_DURATION": "s",
+		"TEST_REDACT_JSON":     `{"token": xyz}`,
+		"TEST_REDACT_ELEMENT":  "1,t",
+		"TEST_REDACT_NESTED":   "1,2;3,t",
+	}))
+
+	testData := []struct {
+		name     string
+		load     func() error
+		expected string
+	}{
+		{"one character", func() error {
+			_, err := envconv.ToInt("TEST_REDACT_SHORT", src, envconv.Redact())
+			return err
+		}, "TEST_REDACT_SHORT: cannot convert [REDACTED] to int: strconv.ParseInt: invalid syntax"},
+		{"duration", func() error {
+			_, err := envconv.ToDuration("TEST_REDACT_DURATION", src, envconv.Redact())
+			return err
+		}, "TEST_REDACT_DURATION: cannot convert [REDACTED] to time.Duration: invalid value"},
+		{"json", func() error {
+			_, err := envconv.ToJSON[map[string]int]("TEST_REDACT_JSON", src, envconv.Redact())
+			return err
+		}, "TEST_REDACT_JSON: cannot convert [REDACTED] to map[string]int: invalid JSON at offset 11"},
+		{"element", func() error {
+			_, err := envconv.ToIntSlice("TEST_REDACT_ELEMENT", ",", src, envconv.Redact())
+			return err
+		}, "TEST_REDACT_ELEMENT: element 1 [REDACTED]: strconv.ParseInt: invalid syntax"},
+		{"nested element", func() error {
+			_, err := envconv.ToIntSlice2D("TEST_REDACT_NESTED", ";", ",", src, envconv.Redact())
+			return err
+		}, "TEST_REDACT_NESTED: element [1][1] [REDACTED]: strconv.ParseInt: invalid syntax"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			assert.EqualError(t, td.load(), td.expected, "they should be equal")
+		})
+	}
+}
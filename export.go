@@ -0,0 +1,114 @@
+package envconv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ExportFormat selects how WriteExport renders a set of variables.
+type ExportFormat int
+
+const (
+	// ExportShell renders a POSIX sh script of export statements, which
+	// can also be sourced by bash, dash and zsh. Values are single
+	// quoted, so nothing in them is expanded.
+	ExportShell ExportFormat = iota
+
+	// ExportFish renders a fish script of "set -gx" statements.
+	ExportFish
+
+	// ExportDocker renders a file for the --env-file flag of docker
+	// run. The format has no quoting, so values are written as they
+	// are, and a value containing a newline is an error.
+	ExportDocker
+
+	// ExportSystemd renders a file for the EnvironmentFile setting of
+	// a systemd unit, with every value double quoted.
+	ExportSystemd
+)
+
+var (
+	// shellQuoter escapes single quotes inside a single quoted POSIX
+	// shell word, by closing the quotes around an escaped quote.
+	shellQuoter = strings.NewReplacer(`'`, `'\''`)
+
+	// fishQuoter escapes the characters that have a special meaning
+	// inside a single quoted fish word.
+	fishQuoter = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+	// systemdQuoter escapes the characters that have a special
+	// meaning inside a double quoted systemd value.
+	systemdQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", "$", `\$`)
+)
+
+// WriteExport writes every variable of src to w in the passed format,
+// one per line, in lexicographical order of name. The output can
+// be read back by the matching tool to give identical values.
+// An error is returned, and nothing is written, if any name
+// is not a valid shell identifier or any value cannot be
+// represented in the format.
+func WriteExport(w io.Writer, src Source, format ExportFormat) error {
+	values := environMap(src)
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, len(names))
+	for i, name := range names {
+		if !isIdentifier(name) {
+			return fmt.Errorf("envconv: cannot export %q: invalid name", name)
+		}
+		line, err := exportLine(name, values[name], format)
+		if err != nil {
+			return err
+		}
+		lines[i] = line
+	}
+
+	bw := bufio.NewWriter(w)
+	for _, line := range lines {
+		fmt.Fprintln(bw, line)
+	}
+	return bw.Flush()
+}
+
+// exportLine returns a single variable in the passed format.
+func exportLine(name string, value string, format ExportFormat) (string, error) {
+	switch format {
+	case ExportShell:
+		return "export " + name + "='" + shellQuoter.Replace(value) + "'", nil
+	case ExportFish:
+		return "set -gx " + name + " '" + fishQuoter.Replace(value) + "'", nil
+	case ExportDocker:
+		if strings.ContainsAny(value, "\r\n") {
+			return "", fmt.Errorf("envconv: cannot export %s: docker env files cannot hold a newline", name)
+		}
+		return name + "=" + value, nil
+	case ExportSystemd:
+		return name + `="` + systemdQuoter.Replace(value) + `"`, nil
+	}
+	return "", fmt.Errorf("envconv: unknown export format %d", format)
+}
+
+// isIdentifier reports whether the passed name is a valid shell
+// identifier, made up of letters, digits and underscores and
+// not starting with a digit.
+func isIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, c := range name {
+		switch {
+		case c == '_', 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z':
+		case '0' <= c && c <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
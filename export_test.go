@@ -0,0 +1,174 @@
+package envconv_test
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// exportValues holds values that need quoting or escaping in at
+// least one of the export formats.
+var exportValues = map[string]string{
+	"PLAIN":     "plain",
+	"EMPTY":     "",
+	"SPACES":    "  padded  value  ",
+	"QUOTES":    `it's "quoted"`,
+	"EXPANSION": "$HOME ${PATH} `id` $(id) !1",
+	"BACKSLASH": `C:\path\ \$HOME\`,
+	"NEWLINE":   "line one\nline two\\\nline three",
+	"UNICODE":   "ünïcødé ✓",
+	"COMMENT":   "# not a comment ; either",
+}
+
+func TestWriteExport(t *testing.T) {
+	src := envconv.NewEnv(map[string]string{
+		"NAME":  `it's "$HOME"`,
+		"EMPTY": "",
+	})
+
+	testData := []struct {
+		name     string
+		format   envconv.ExportFormat
+		expected string
+	}{
+		{"shell", envconv.ExportShell, "export EMPTY=''\nexport NAME='it'\\''s \"$HOME\"'\n"},
+		{"fish", envconv.ExportFish, "set -gx EMPTY ''\nset -gx NAME 'it\\'s \"$HOME\"'\n"},
+		{"docker", envconv.ExportDocker, "EMPTY=\nNAME=it's \"$HOME\"\n"},
+		{"systemd", envconv.ExportSystemd, "EMPTY=\"\"\nNAME=\"it's \\\"\\$HOME\\\"\"\n"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			var b bytes.Buffer
+			assert.NoError(t, envconv.WriteExport(&b, src, td.format), "there should be no error")
+			assert.Equal(t, td.expected, b.String(), "they should be equal")
+		})
+	}
+
+	t.Run("invalid name", func(t *testing.T) {
+		var b bytes.Buffer
+		err := envconv.WriteExport(&b, envconv.NewEnv(map[string]string{"A": "1", "NOT-VALID": "1"}), envconv.ExportShell)
+		assert.Error(t, err, "there should be an error")
+		assert.Empty(t, b.String(), "nothing should be written")
+	})
+
+	t.Run("docker newline", func(t *testing.T) {
+		var b bytes.Buffer
+		err := envconv.WriteExport(&b, envconv.NewEnv(map[string]string{"A": "1\n2"}), envconv.ExportDocker)
+		assert.Error(t, err, "there should be an error")
+	})
+}
+
+// readShellExport sources the passed script with the named shell and
+// returns the value of each of the passed variables.
+func readShellExport(t *testing.T, shell string, script string, names []string) map[string]string {
+	t.Helper()
+	var printf strings.Builder
+	printf.WriteString("\nprintf '%s\\0'")
+	for _, name := range names {
+		printf.WriteString(` "$` + name + `"`)
+	}
+	out, err := exec.Command(shell, "-c", script+printf.String()).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]string{}
+	for i, v := range strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00") {
+		values[names[i]] = v
+	}
+	return values
+}
+
+func TestWriteExportShellRoundTrip(t *testing.T) {
+	src := envconv.NewEnv(exportValues)
+	testData := []struct {
+		shell  string
+		format envconv.ExportFormat
+	}{
+		{"sh", envconv.ExportShell},
+		{"bash", envconv.ExportShell},
+		{"dash", envconv.ExportShell},
+		{"zsh", envconv.ExportShell},
+		{"fish", envconv.ExportFish},
+	}
+
+	for _, td := range testData {
+		t.Run(td.shell, func(t *testing.T) {
+			if _, err := exec.LookPath(td.shell); err != nil {
+				t.Skipf("%s is not installed", td.shell)
+			}
+			var b bytes.Buffer
+			assert.NoError(t, envconv.WriteExport(&b, src, td.format), "there should be no error")
+			assert.Equal(t, exportValues, readShellExport(t, td.shell, b.String(), src.Names()), "they should be equal")
+		})
+	}
+}
+
+// readDockerEnvFile parses an env file in the same way as the
+// --env-file flag of docker run: leading whitespace is trimmed,
+// blank and comment lines are skipped, and the value is the
+// rest of the line after the first "=".
+func readDockerEnvFile(text string) map[string]string {
+	values := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, "=")
+		values[name] = value
+	}
+	return values
+}
+
+// readSystemdEnvFile parses the double quoted assignments written
+// for ExportSystemd in the same way as systemd: a backslash escapes
+// a following double quote, backslash, backtick or dollar sign,
+// and newlines inside the quotes are kept.
+func readSystemdEnvFile(t *testing.T, text string) map[string]string {
+	values := map[string]string{}
+	for text != "" {
+		name, rest, ok := strings.Cut(text, `="`)
+		if !ok {
+			t.Fatalf("invalid assignment %q", text)
+		}
+		var value strings.Builder
+		i := 0
+		for ; rest[i] != '"'; i++ {
+			if rest[i] == '\\' && strings.ContainsRune("\"\\`$", rune(rest[i+1])) {
+				i++
+			}
+			value.WriteByte(rest[i])
+		}
+		values[name] = value.String()
+		text = strings.TrimPrefix(rest[i+1:], "\n")
+	}
+	return values
+}
+
+func TestWriteExportFileRoundTrip(t *testing.T) {
+	t.Run("docker", func(t *testing.T) {
+		values := map[string]string{}
+		for name, value := range exportValues {
+			if !strings.Contains(value, "\n") {
+				values[name] = value
+			}
+		}
+		var b bytes.Buffer
+		assert.NoError(t, envconv.WriteExport(&b, envconv.NewEnv(values), envconv.ExportDocker), "there should be no error")
+		assert.Equal(t, values, readDockerEnvFile(b.String()), "they should be equal")
+	})
+
+	t.Run("systemd", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.NoError(t, envconv.WriteExport(&b, envconv.NewEnv(exportValues), envconv.ExportSystemd), "there should be no error")
+		assert.Equal(t, exportValues, readSystemdEnvFile(t, b.String()), "they should be equal")
+	})
+}
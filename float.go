@@ -1,140 +1,154 @@
 package envconv
 
-import (
-	"strconv"
-	"strings"
-)
+import "strconv"
 
 // Type intType is a convenience interface to wrap all of the possible int types
 type floatType interface {
 	float32 | float64
 }
 
+// floatConverter returns a conversion function that converts a string
+// to type T, using the passed bit size and strconv function.
+func floatConverter[T floatType](bitSize int, conversionFunc func(string, int) (float64, error)) func(string) (T, error) {
+	return func(value string) (T, error) {
+		convertedValue, err := conversionFunc(value, bitSize)
+		if err != nil {
+			return T(0), err
+		}
+		return T(convertedValue), nil
+	}
+}
+
 // toFloatType returns the value of the requested environment variable
 // converted to type T. An error will be returned if the
 // environment variable is not found or the conversion to
 // type T fails.
-func toFloatType[T floatType](varName string, bitSize int, conversionFunc func(string, int) (float64, error)) (T, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return T(0), err
-	}
-
-	convertedValue, err := conversionFunc(value, bitSize)
-	if err != nil {
-		return T(0), err
-	}
-
-	return T(convertedValue), err
+func toFloatType[T floatType](varName string, bitSize int, conversionFunc func(string, int) (float64, error), opts []Option) (T, error) {
+	return convert(varName, opts, floatConverter[T](bitSize, conversionFunc))
 }
 
 // toFloatSliceType returns the value of the requested environment variable
 // converted to type []T. An error will be returned if the
 // environment variable is not found or the conversion to
 // type []T fails.
-func toFloatSliceType[T floatType](varName string, separator string, bitSize int, conversionFunc func(string, int) (float64, error)) ([]T, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return []T{}, err
-	}
-
-	valueSlice := strings.Split(value, separator)
-	var convertedValues []T
-	for _, v := range valueSlice {
-		convertedValue, err := conversionFunc(strings.TrimSpace(v), bitSize)
-		if err != nil {
-			return []T{}, err
-		}
-		convertedValues = append(convertedValues, T(convertedValue))
-	}
-
-	return convertedValues, err
+func toFloatSliceType[T floatType](varName string, separator string, bitSize int, conversionFunc func(string, int) (float64, error), opts []Option) ([]T, error) {
+	return convertSlice(varName, separator, opts, floatConverter[T](bitSize, conversionFunc))
 }
 
 // ToFloatTypeWithDefault returns the value of the requested environment
 // variable converted to type T. The default value passed as the
 // second parameter will be returned if the environmentvariable
 // is not found or the conversion to type T fails.
-func toFloatTypeWithDefault[T floatType](varName string, defaultValue T, bitSize int, conversionFunc func(string, int) (float64, error)) T {
-	value, err := toFloatType[T](varName, bitSize, conversionFunc)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func toFloatTypeWithDefault[T floatType](varName string, defaultValue T, bitSize int, conversionFunc func(string, int) (float64, error), opts []Option) T {
+	value, err := toFloatType[T](varName, bitSize, conversionFunc, opts)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // toFloatSliceType returns the value of the requested environment variable
 // converted to type []T. The default value passed as the second
 // parameter will be returned if the environment variable is
 // not found or the conversion to type []T fails.
-func toFloatSliceTypeWithDefault[T floatType](varName string, separator string, defaultValue []T, bitSize int, conversionFunc func(string, int) (float64, error)) []T {
-	value, err := toFloatSliceType[T](varName, separator, bitSize, conversionFunc)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func toFloatSliceTypeWithDefault[T floatType](varName string, separator string, defaultValue []T, bitSize int, conversionFunc func(string, int) (float64, error), opts []Option) []T {
+	value, err := toFloatSliceType[T](varName, separator, bitSize, conversionFunc, opts)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToFloat32 returns the value of the requested environment variable
 // converted to an float32. An error will be returned if the
 // environment variable is not found or the conversion to
 // float32 fails.
-func ToFloat32(varName string) (float32, error) {
-	return toFloatType[float32](varName, 32, strconv.ParseFloat)
+func ToFloat32(varName string, opts ...Option) (float32, error) {
+	return toFloatType[float32](varName, 32, strconv.ParseFloat, opts)
 }
 
 // ToFloat32Slice returns the value of the requested environment variable
 // converted to a slice of float32s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of float32s fails.
-func ToFloat32Slice(varName string, separator string) ([]float32, error) {
-	return toFloatSliceType[float32](varName, separator, 32, strconv.ParseFloat)
+func ToFloat32Slice(varName string, separator string, opts ...Option) ([]float32, error) {
+	return toFloatSliceType[float32](varName, separator, 32, strconv.ParseFloat, opts)
 }
 
 // ToFloat32WithDefault returns the value of the requested environment
 // variable converted to an float32. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to float32 fails.
-func ToFloat32WithDefault(varName string, defaultValue float32) float32 {
-	return toFloatTypeWithDefault[float32](varName, defaultValue, 32, strconv.ParseFloat)
+func ToFloat32WithDefault(varName string, defaultValue float32, opts ...Option) float32 {
+	return toFloatTypeWithDefault[float32](varName, defaultValue, 32, strconv.ParseFloat, opts)
 }
 
 // ToFloat32SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of float32s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of float32s fails.
-func ToFloat32SliceWithDefault(varName string, separator string, defaultValue []float32) []float32 {
-	return toFloatSliceTypeWithDefault[float32](varName, separator, defaultValue, 32, strconv.ParseFloat)
+func ToFloat32SliceWithDefault(varName string, separator string, defaultValue []float32, opts ...Option) []float32 {
+	return toFloatSliceTypeWithDefault[float32](varName, separator, defaultValue, 32, strconv.ParseFloat, opts)
+}
+
+// ToFloat32Slice2D returns the value of the requested environment variable
+// converted to a nested slice of float32s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToFloat32Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]float32, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, floatConverter[float32](32, strconv.ParseFloat))
+}
+
+// ToFloat32Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of float32s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToFloat32Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]float32, opts ...Option) [][]float32 {
+	value, err := ToFloat32Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToFloat64 returns the value of the requested environment variable
 // converted to an float64. An error will be returned if the
 // environment variable is not found or the conversion to
 // float64 fails.
-func ToFloat64(varName string) (float64, error) {
-	return toFloatType[float64](varName, 64, strconv.ParseFloat)
+func ToFloat64(varName string, opts ...Option) (float64, error) {
+	return toFloatType[float64](varName, 64, strconv.ParseFloat, opts)
 }
 
 // ToFloat64Slice returns the value of the requested environment variable
 // converted to a slice of float64s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of float64s fails.
-func ToFloat64Slice(varName string, separator string) ([]float64, error) {
-	return toFloatSliceType[float64](varName, separator, 64, strconv.ParseFloat)
+func ToFloat64Slice(varName string, separator string, opts ...Option) ([]float64, error) {
+	return toFloatSliceType[float64](varName, separator, 64, strconv.ParseFloat, opts)
 }
 
 // ToFloat64WithDefault returns the value of the requested environment
 // variable converted to an float64. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to float64 fails.
-func ToFloat64WithDefault(varName string, defaultValue float64) float64 {
-	return toFloatTypeWithDefault[float64](varName, defaultValue, 64, strconv.ParseFloat)
+func ToFloat64WithDefault(varName string, defaultValue float64, opts ...Option) float64 {
+	return toFloatTypeWithDefault[float64](varName, defaultValue, 64, strconv.ParseFloat, opts)
 }
 
 // ToFloat64SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of float64s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of float64s fails.
-func ToFloat64SliceWithDefault(varName string, separator string, defaultValue []float64) []float64 {
-	return toFloatSliceTypeWithDefault[float64](varName, separator, defaultValue, 64, strconv.ParseFloat)
+func ToFloat64SliceWithDefault(varName string, separator string, defaultValue []float64, opts ...Option) []float64 {
+	return toFloatSliceTypeWithDefault[float64](varName, separator, defaultValue, 64, strconv.ParseFloat, opts)
+}
+
+// ToFloat64Slice2D returns the value of the requested environment variable
+// converted to a nested slice of float64s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToFloat64Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]float64, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, floatConverter[float64](64, strconv.ParseFloat))
+}
+
+// ToFloat64Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of float64s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToFloat64Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]float64, opts ...Option) [][]float64 {
+	value, err := ToFloat64Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
@@ -181,3 +181,35 @@ func TestToFloat64SliceWithDefault(t *testing.T) {
 	}
 	runSliceWithDefaultEmptyTest[float64](t, ",", defaultValue, envconv.ToFloat64SliceWithDefault)
 }
+
+func TestToFloat64Slice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]float64
+		errorExpected bool
+	}{
+		{"TEST_FLOAT64_SLICE_2D_MATRIX", "1.5,2;3,4.25", [][]float64{{1.5, 2}, {3, 4.25}}, false},
+		{"TEST_FLOAT64_SLICE_2D_NOTANUMBER", "1.5,2;three", [][]float64{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToFloat64Slice2D)
+	}
+}
+
+func TestToFloat32Slice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]float32
+		errorExpected bool
+	}{
+		{"TEST_FLOAT32_SLICE_2D_VALID", "0.5,1;2.25", [][]float32{{0.5, 1}, {2.25}}, false},
+		{"TEST_FLOAT32_SLICE_2D_OUT_OF_RANGE", "1;1e39", [][]float32{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToFloat32Slice2D)
+	}
+}
@@ -0,0 +1,179 @@
+package envconv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errEmptySeparator is returned when a slice is formatted with an
+// empty separator, which could not be split again.
+var errEmptySeparator = errors.New("envconv: empty separator")
+
+// errEmptyRow is returned when a nested slice holding an empty slice
+// is formatted, as it would be read back as a slice holding one
+// empty element.
+var errEmptyRow = errors.New("envconv: cannot format an empty nested slice")
+
+var (
+	durationType  = reflect.TypeOf(time.Duration(0))
+	secretType    = reflect.TypeOf(Secret{})
+	byteSliceType = reflect.TypeOf([]byte(nil))
+)
+
+// Format returns the passed value as text, in the form that the To*
+// function for its type parses back into an equal value. Any of the
+// types supported by the To* functions, or a slice of them, can be
+// formatted. Slice elements are separated by the passed separator,
+// which is ignored for other types, and are quoted with the same
+// rules as Join where necessary. Such a value must be read back with
+// the Quoted option.
+//
+// An empty slice is formatted as an empty string, which is only read
+// back as an empty slice with the EmptyAsZero policy. A []byte is
+// formatted as raw text, as ToByteSlice reads it. As []uint8 is
+// the same type, it cannot be formatted for ToUint8Slice.
+func Format[T any](value T, separator string) (string, error) {
+	v := reflect.ValueOf(&value).Elem()
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Type() == byteSliceType {
+		return string(v.Bytes()), nil
+	}
+	if v.Kind() != reflect.Slice {
+		return formatScalar(v)
+	}
+	if separator == "" {
+		return "", errEmptySeparator
+	}
+	elements, err := formatElements(v)
+	if err != nil {
+		return "", err
+	}
+	return Join(elements, separator), nil
+}
+
+// Format2D returns the passed nested slice as text, in the form that
+// the nested slice functions parse back into an equal value when
+// the Quoted option is used. The outer slices are separated
+// by outerSeparator, and their elements by innerSeparator.
+// As with Format, an empty value is only read back with the
+// EmptyAsZero policy. A nested slice that is empty cannot
+// be represented at all, and is an error.
+func Format2D[T any](value [][]T, outerSeparator string, innerSeparator string) (string, error) {
+	return format2D(reflect.ValueOf(value), outerSeparator, innerSeparator, true)
+}
+
+// format2D formats the passed nested slice as Format2D does, quoting
+// elements only if quoted is true.
+func format2D(v reflect.Value, outerSeparator string, innerSeparator string, quoted bool) (string, error) {
+	if outerSeparator == "" || innerSeparator == "" {
+		return "", errEmptySeparator
+	}
+	rows := make([]string, v.Len())
+	for i := range rows {
+		row := v.Index(i)
+		if row.Len() == 0 {
+			return "", errEmptyRow
+		}
+		elements, err := formatElements(row)
+		if err != nil {
+			return "", err
+		}
+		if quoted {
+			for j, e := range elements {
+				elements[j] = quote(e, innerSeparator, outerSeparator)
+			}
+		}
+		rows[i] = strings.Join(elements, innerSeparator)
+	}
+	return strings.Join(rows, outerSeparator), nil
+}
+
+// formatElements formats each element of the passed slice.
+func formatElements(v reflect.Value) ([]string, error) {
+	elements := make([]string, v.Len())
+	for i := range elements {
+		e, err := formatScalar(v.Index(i))
+		if err != nil {
+			return nil, err
+		}
+		elements[i] = e
+	}
+	return elements, nil
+}
+
+// formatScalar formats a single value of one of the types supported
+// by the To* functions, in the form that its converter parses.
+func formatScalar(v reflect.Value) (string, error) {
+	switch v.Type() {
+	case durationType:
+		return time.Duration(v.Int()).String(), nil
+	case secretType:
+		return v.Interface().(Secret).Reveal(), nil
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.String:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("envconv: cannot format type %s", v.Type())
+}
+
+// Set formats the passed value with Format and sets the named
+// environment variable to the result, using os.Setenv or the
+// Setter passed with the Into option. A value set in this
+// way is read back unchanged by the To* function for its
+// type, subject to the rules described for Format.
+func Set[T any](varName string, value T, separator string, opts ...Option) error {
+	text, err := Format(value, separator)
+	if err != nil {
+		return fmt.Errorf("%s: %w", varName, err)
+	}
+	return setEnv(varName, text, newOptions(opts))
+}
+
+// Set2D formats the passed nested slice with Format2D and sets the
+// named environment variable to the result, in the same way as Set.
+func Set2D[T any](varName string, value [][]T, outerSeparator string, innerSeparator string, opts ...Option) error {
+	text, err := Format2D(value, outerSeparator, innerSeparator)
+	if err != nil {
+		return fmt.Errorf("%s: %w", varName, err)
+	}
+	return setEnv(varName, text, newOptions(opts))
+}
+
+// SetJSON encodes the passed value as JSON and sets the named
+// environment variable to the result, in the same way as Set.
+// The value is read back unchanged by ToJSON.
+func SetJSON[T any](varName string, value T, opts ...Option) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", varName, err)
+	}
+	return setEnv(varName, string(b), newOptions(opts))
+}
+
+// setEnv sets the named variable in the Setter in the passed options,
+// or in the process environment.
+func setEnv(varName string, value string, o *options) error {
+	var dst Setter = environment{}
+	if o.target != nil {
+		dst = o.target
+	}
+	return dst.Set(varName, value)
+}
@@ -0,0 +1,190 @@
+package envconv_test
+
+import (
+	"math"
+	"os"
+	"slices"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	testData := []struct {
+		name      string
+		value     any
+		separator string
+		expected  string
+	}{
+		{"int", -42, "", "-42"},
+		{"int8", int8(math.MinInt8), "", "-128"},
+		{"uint64", uint64(math.MaxUint64), "", "18446744073709551615"},
+		{"byte", byte(255), "", "255"},
+		{"float32", float32(0.1), "", "0.1"},
+		{"float64", 1e21, "", "1e+21"},
+		{"float64 infinity", math.Inf(-1), "", "-Inf"},
+		{"bool", true, "", "true"},
+		{"duration", 90 * time.Second, "", "1m30s"},
+		{"string", " padded ", "", " padded "},
+		{"secret", envconv.NewSecret("hunter2"), "", "hunter2"},
+		{"int slice", []int{80, 443}, ",", "80,443"},
+		{"string slice", []string{"a,b", "", `say "hi"`, " c "}, ",", `"a,b","","say \"hi\""," c "`},
+		{"duration slice", []time.Duration{time.Second, time.Millisecond}, ";", "1s;1ms"},
+		{"empty slice", []bool{}, ",", ""},
+		{"byte slice", []byte("hi"), ",", "hi"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			v, err := envconv.Format(td.value, td.separator)
+			assert.NoError(t, err, "there should be no error")
+			assert.Equal(t, td.expected, v, "they should be equal")
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := envconv.Format(map[string]int{}, "")
+		assert.EqualError(t, err, "envconv: cannot format type map[string]int")
+	})
+
+	t.Run("empty separator", func(t *testing.T) {
+		_, err := envconv.Format([]int{1}, "")
+		assert.Error(t, err, "there should be an error")
+	})
+}
+
+func TestFormat2D(t *testing.T) {
+	v, err := envconv.Format2D([][]string{{"a", "b;c"}, {"d,e"}}, ";", ",")
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, `a,"b;c";"d,e"`, v, "they should be equal")
+
+	_, err = envconv.Format2D([][]int{{1}, {}}, ";", ",")
+	assert.Error(t, err, "there should be an error")
+}
+
+func TestSet(t *testing.T) {
+	env := envconv.NewMutableEnv(nil)
+	into, from := envconv.Into(env), envconv.FromSource(env)
+
+	assert.NoError(t, envconv.Set("PORT", uint16(8080), "", into))
+	port, err := envconv.ToUint16("PORT", from)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, uint16(8080), port, "they should be equal")
+
+	rates := []float64{0.1, 1.0 / 3, math.MaxFloat64, math.SmallestNonzeroFloat64}
+	assert.NoError(t, envconv.Set("RATES", rates, " ", into))
+	v, err := envconv.ToFloat64Slice("RATES", " ", from)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, rates, v, "they should be equal")
+
+	shards := [][]int{{1, 2, 3}, {4, 5}, {6}}
+	assert.NoError(t, envconv.Set2D("SHARDS", shards, ";", ",", into))
+	s, err := envconv.ToIntSlice2D("SHARDS", ";", ",", from)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, shards, s, "they should be equal")
+
+	type route struct {
+		Path    string `json:"path"`
+		Backend string `json:"backend"`
+	}
+	routes := []route{{"/api", "api:8080"}}
+	assert.NoError(t, envconv.SetJSON("ROUTES", routes, into))
+	r, err := envconv.ToJSON[[]route]("ROUTES", from)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, routes, r, "they should be equal")
+
+	empty := []string{}
+	assert.NoError(t, envconv.Set("EMPTY", empty, ",", into))
+	e, err := envconv.ToStringSlice("EMPTY", ",", from, envconv.WithEmpty(envconv.EmptyAsZero))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, empty, e, "they should be equal")
+
+	t.Run("process environment", func(t *testing.T) {
+		assert.NoError(t, envconv.Set("TEST_SET_TIMEOUT", 1500*time.Millisecond, ""))
+		assert.Equal(t, "1.5s", os.Getenv("TEST_SET_TIMEOUT"), "they should be equal")
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		assert.Error(t, envconv.Set("A=B", 1, "", into), "there should be an error")
+	})
+}
+
+func TestSetRoundTrip(t *testing.T) {
+	env := envconv.NewMutableEnv(nil)
+	into, from := envconv.Into(env), envconv.FromSource(env)
+	config := &quick.Config{MaxCount: 500}
+
+	// An empty slice is formatted as an empty value, which the slice
+	// functions only read back as an empty slice with EmptyAsZero.
+	zero := envconv.WithEmpty(envconv.EmptyAsZero)
+
+	t.Run("int64", func(t *testing.T) {
+		f := func(value int64) bool {
+			if envconv.Set("VALUE", value, "", into) != nil {
+				return false
+			}
+			v, err := envconv.ToInt64("VALUE", from)
+			return err == nil && v == value
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		f := func(value float64) bool {
+			if envconv.Set("VALUE", value, "", into) != nil {
+				return false
+			}
+			v, err := envconv.ToFloat64("VALUE", from)
+			return err == nil && v == value
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+
+	t.Run("byte slice", func(t *testing.T) {
+		f := func(value []byte) bool {
+			if envconv.Set("VALUE", value, ",", into) != nil {
+				return false
+			}
+			v, err := envconv.ToByteSlice("VALUE", from)
+			return err == nil && assert.ObjectsAreEqual(value, v)
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+
+	t.Run("duration slice", func(t *testing.T) {
+		f := func(values []time.Duration) bool {
+			if envconv.Set("VALUE", values, ",", into) != nil {
+				return false
+			}
+			v, err := envconv.ToDurationSlice("VALUE", ",", from, zero)
+			return err == nil && assert.ObjectsAreEqual(values, v)
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+
+	t.Run("string slice", func(t *testing.T) {
+		f := func(values []string) bool {
+			if envconv.Set("VALUE", values, ",", into) != nil {
+				return false
+			}
+			v, err := envconv.ToStringSlice("VALUE", ",", from, envconv.Quoted(), zero)
+			return err == nil && assert.ObjectsAreEqual(values, v)
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+
+	t.Run("nested string slice", func(t *testing.T) {
+		f := func(values [][]string) bool {
+			// An empty nested slice cannot be represented.
+			if err := envconv.Set2D("VALUE", values, ";", ",", into); err != nil {
+				return slices.ContainsFunc(values, func(row []string) bool { return len(row) == 0 })
+			}
+			v, err := envconv.ToStringSlice2D("VALUE", ";", ",", from, envconv.Quoted(), zero)
+			return err == nil && assert.ObjectsAreEqual(values, v)
+		}
+		assert.NoError(t, quick.Check(f, config))
+	})
+}
@@ -1,236 +1,304 @@
 package envconv
 
-import (
-	"strconv"
-	"strings"
-)
+import "strconv"
 
 // Type intType is a convenience interface to wrap all of the possible int types
 type intType interface {
 	int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64
 }
 
+// intConverter returns a conversion function that converts a string
+// to type T, using the passed bit size and strconv function.
+func intConverter[T intType, RT int64 | uint64](bitSize int, conversionFunc func(string, int, int) (RT, error)) func(string) (T, error) {
+	return func(value string) (T, error) {
+		convertedValue, err := conversionFunc(value, 10, bitSize)
+		if err != nil {
+			return T(0), err
+		}
+		return T(convertedValue), nil
+	}
+}
+
 // tointType returns the value of the requested environment variable
 // converted to type T. An error will be returned if the
 // environment variable is not found or the conversion to
 // type T fails.
-func toIntType[T intType, RT int64 | uint64](varName string, bitSize int, conversionFunc func(string, int, int) (RT, error)) (T, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return T(0), err
-	}
-
-	convertedValue, err := conversionFunc(value, 10, bitSize)
-	if err != nil {
-		return T(0), err
-	}
-
-	return T(convertedValue), err
+func toIntType[T intType, RT int64 | uint64](varName string, bitSize int, conversionFunc func(string, int, int) (RT, error), opts []Option) (T, error) {
+	return convert(varName, opts, intConverter[T](bitSize, conversionFunc))
 }
 
 // toIntSliceType returns the value of the requested environment variable
 // converted to type []T. An error will be returned if the
 // environment variable is not found or the conversion to
 // type []T fails.
-func toIntSliceType[T intType, RT int64 | uint64](varName string, separator string, bitSize int, conversionFunc func(string, int, int) (RT, error)) ([]T, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return []T{}, err
-	}
-
-	valueSlice := strings.Split(value, separator)
-	var convertedValues []T
-	for _, v := range valueSlice {
-		convertedValue, err := conversionFunc(strings.TrimSpace(v), 10, bitSize)
-		if err != nil {
-			return []T{}, err
-		}
-		convertedValues = append(convertedValues, T(convertedValue))
-	}
-
-	return convertedValues, err
+func toIntSliceType[T intType, RT int64 | uint64](varName string, separator string, bitSize int, conversionFunc func(string, int, int) (RT, error), opts []Option) ([]T, error) {
+	return convertSlice(varName, separator, opts, intConverter[T](bitSize, conversionFunc))
 }
 
 // TointTypeWithDefault returns the value of the requested environment
 // variable converted to type T. The default value passed as the
 // second parameter will be returned if the environmentvariable
 // is not found or the conversion to type T fails.
-func toIntTypeWithDefault[T intType, RT int64 | uint64](varName string, defaultValue T, bitSize int, conversionFunc func(string, int, int) (RT, error)) T {
-	value, err := toIntType[T](varName, bitSize, conversionFunc)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func toIntTypeWithDefault[T intType, RT int64 | uint64](varName string, defaultValue T, bitSize int, conversionFunc func(string, int, int) (RT, error), opts []Option) T {
+	value, err := toIntType[T](varName, bitSize, conversionFunc, opts)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // toIntSliceType returns the value of the requested environment variable
 // converted to type []T. The default value passed as the second
 // parameter will be returned if the environment variable is
 // not found or the conversion to type []T fails.
-func toIntSliceTypeWithDefault[T intType, RT int64 | uint64](varName string, separator string, defaultValue []T, bitSize int, conversionFunc func(string, int, int) (RT, error)) []T {
-	value, err := toIntSliceType[T](varName, separator, bitSize, conversionFunc)
-	if err != nil {
-		return defaultValue
-	}
-	return value
+func toIntSliceTypeWithDefault[T intType, RT int64 | uint64](varName string, separator string, defaultValue []T, bitSize int, conversionFunc func(string, int, int) (RT, error), opts []Option) []T {
+	value, err := toIntSliceType[T](varName, separator, bitSize, conversionFunc, opts)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToInt returns the value of the requested environment variable
 // converted to an int. An error will be returned if the
 // environment variable is not found or the conversion to
 // int fails.
-func ToInt(varName string) (int, error) {
-	return toIntType[int](varName, 64, strconv.ParseInt)
+func ToInt(varName string, opts ...Option) (int, error) {
+	return toIntType[int](varName, 64, strconv.ParseInt, opts)
 }
 
 // ToIntSlice returns the value of the requested environment variable
 // converted to a slice of int. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of ints fails.
-func ToIntSlice(varName string, separator string) ([]int, error) {
-	return toIntSliceType[int](varName, separator, 64, strconv.ParseInt)
+func ToIntSlice(varName string, separator string, opts ...Option) ([]int, error) {
+	return toIntSliceType[int](varName, separator, 64, strconv.ParseInt, opts)
 }
 
 // ToIntWithDefault returns the value of the requested environment
 // variable converted to an ints. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to int fails.
-func ToIntWithDefault(varName string, defaultValue int) int {
-	return toIntTypeWithDefault[int](varName, defaultValue, 64, strconv.ParseInt)
+func ToIntWithDefault(varName string, defaultValue int, opts ...Option) int {
+	return toIntTypeWithDefault[int](varName, defaultValue, 64, strconv.ParseInt, opts)
 }
 
 // ToIntSliceWithDefault returns the value of the requested environment
 // variable converted to a slice of ints. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of ints fails.
-func ToIntSliceWithDefault(varName string, separator string, defaultValue []int) []int {
-	return toIntSliceTypeWithDefault[int](varName, separator, defaultValue, 64, strconv.ParseInt)
+func ToIntSliceWithDefault(varName string, separator string, defaultValue []int, opts ...Option) []int {
+	return toIntSliceTypeWithDefault[int](varName, separator, defaultValue, 64, strconv.ParseInt, opts)
+}
+
+// ToIntSlice2D returns the value of the requested environment variable
+// converted to a nested slice of ints. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToIntSlice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]int, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, intConverter[int](64, strconv.ParseInt))
+}
+
+// ToIntSlice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of ints. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToIntSlice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]int, opts ...Option) [][]int {
+	value, err := ToIntSlice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToInt8 returns the value of the requested environment variable
 // converted to an int8. An error will be returned if the
 // environment variable is not found or the conversion to
 // int8 fails.
-func ToInt8(varName string) (int8, error) {
-	return toIntType[int8](varName, 8, strconv.ParseInt)
+func ToInt8(varName string, opts ...Option) (int8, error) {
+	return toIntType[int8](varName, 8, strconv.ParseInt, opts)
 }
 
 // ToInt8Slice returns the value of the requested environment variable
 // converted to a slice of int8s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of int8s fails.
-func ToInt8Slice(varName string, separator string) ([]int8, error) {
-	return toIntSliceType[int8](varName, separator, 8, strconv.ParseInt)
+func ToInt8Slice(varName string, separator string, opts ...Option) ([]int8, error) {
+	return toIntSliceType[int8](varName, separator, 8, strconv.ParseInt, opts)
 }
 
 // ToInt8WithDefault returns the value of the requested environment
 // variable converted to an int8. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to int8 fails.
-func ToInt8WithDefault(varName string, defaultValue int8) int8 {
-	return toIntTypeWithDefault[int8](varName, defaultValue, 8, strconv.ParseInt)
+func ToInt8WithDefault(varName string, defaultValue int8, opts ...Option) int8 {
+	return toIntTypeWithDefault[int8](varName, defaultValue, 8, strconv.ParseInt, opts)
 }
 
 // ToInt8SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of int8s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of int8s fails.
-func ToInt8SliceWithDefault(varName string, separator string, defaultValue []int8) []int8 {
-	return toIntSliceTypeWithDefault[int8](varName, separator, defaultValue, 8, strconv.ParseInt)
+func ToInt8SliceWithDefault(varName string, separator string, defaultValue []int8, opts ...Option) []int8 {
+	return toIntSliceTypeWithDefault[int8](varName, separator, defaultValue, 8, strconv.ParseInt, opts)
+}
+
+// ToInt8Slice2D returns the value of the requested environment variable
+// converted to a nested slice of int8s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToInt8Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]int8, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, intConverter[int8](8, strconv.ParseInt))
+}
+
+// ToInt8Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of int8s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToInt8Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]int8, opts ...Option) [][]int8 {
+	value, err := ToInt8Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToInt16 returns the value of the requested environment variable
 // converted to an int16. An error will be returned if the
 // environment variable is not found or the conversion to
 // int16 fails.
-func ToInt16(varName string) (int16, error) {
-	return toIntType[int16](varName, 16, strconv.ParseInt)
+func ToInt16(varName string, opts ...Option) (int16, error) {
+	return toIntType[int16](varName, 16, strconv.ParseInt, opts)
 }
 
 // ToInt16Slice returns the value of the requested environment variable
 // converted to a slice of int16s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of int16s fails.
-func ToInt16Slice(varName string, separator string) ([]int16, error) {
-	return toIntSliceType[int16](varName, separator, 16, strconv.ParseInt)
+func ToInt16Slice(varName string, separator string, opts ...Option) ([]int16, error) {
+	return toIntSliceType[int16](varName, separator, 16, strconv.ParseInt, opts)
 }
 
 // ToInt16WithDefault returns the value of the requested environment
 // variable converted to an int16. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to int16 fails.
-func ToInt16WithDefault(varName string, defaultValue int16) int16 {
-	return toIntTypeWithDefault[int16](varName, defaultValue, 16, strconv.ParseInt)
+func ToInt16WithDefault(varName string, defaultValue int16, opts ...Option) int16 {
+	return toIntTypeWithDefault[int16](varName, defaultValue, 16, strconv.ParseInt, opts)
 }
 
 // ToInt16SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of int16s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of int16s fails.
-func ToInt16SliceWithDefault(varName string, separator string, defaultValue []int16) []int16 {
-	return toIntSliceTypeWithDefault[int16](varName, separator, defaultValue, 16, strconv.ParseInt)
+func ToInt16SliceWithDefault(varName string, separator string, defaultValue []int16, opts ...Option) []int16 {
+	return toIntSliceTypeWithDefault[int16](varName, separator, defaultValue, 16, strconv.ParseInt, opts)
+}
+
+// ToInt16Slice2D returns the value of the requested environment variable
+// converted to a nested slice of int16s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToInt16Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]int16, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, intConverter[int16](16, strconv.ParseInt))
+}
+
+// ToInt16Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of int16s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToInt16Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]int16, opts ...Option) [][]int16 {
+	value, err := ToInt16Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToInt32 returns the value of the requested environment variable
 // converted to an int32. An error will be returned if the
 // environment variable is not found or the conversion to
 // int32 fails.
-func ToInt32(varName string) (int32, error) {
-	return toIntType[int32](varName, 32, strconv.ParseInt)
+func ToInt32(varName string, opts ...Option) (int32, error) {
+	return toIntType[int32](varName, 32, strconv.ParseInt, opts)
 }
 
 // ToInt32Slice returns the value of the requested environment variable
 // converted to a slice of int32s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of int32s fails.
-func ToInt32Slice(varName string, separator string) ([]int32, error) {
-	return toIntSliceType[int32](varName, separator, 32, strconv.ParseInt)
+func ToInt32Slice(varName string, separator string, opts ...Option) ([]int32, error) {
+	return toIntSliceType[int32](varName, separator, 32, strconv.ParseInt, opts)
 }
 
 // ToInt32WithDefault returns the value of the requested environment
 // variable converted to an int32. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to int32 fails.
-func ToInt32WithDefault(varName string, defaultValue int32) int32 {
-	return toIntTypeWithDefault[int32](varName, defaultValue, 32, strconv.ParseInt)
+func ToInt32WithDefault(varName string, defaultValue int32, opts ...Option) int32 {
+	return toIntTypeWithDefault[int32](varName, defaultValue, 32, strconv.ParseInt, opts)
 }
 
 // ToInt32SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of int32s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of int32s fails.
-func ToInt32SliceWithDefault(varName string, separator string, defaultValue []int32) []int32 {
-	return toIntSliceTypeWithDefault[int32](varName, separator, defaultValue, 32, strconv.ParseInt)
+func ToInt32SliceWithDefault(varName string, separator string, defaultValue []int32, opts ...Option) []int32 {
+	return toIntSliceTypeWithDefault[int32](varName, separator, defaultValue, 32, strconv.ParseInt, opts)
+}
+
+// ToInt32Slice2D returns the value of the requested environment variable
+// converted to a nested slice of int32s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToInt32Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]int32, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, intConverter[int32](32, strconv.ParseInt))
+}
+
+// ToInt32Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of int32s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToInt32Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]int32, opts ...Option) [][]int32 {
+	value, err := ToInt32Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
 // ToInt64 returns the value of the requested environment variable
 // converted to an int64. An error will be returned if the
 // environment variable is not found or the conversion to
 // int64 fails.
-func ToInt64(varName string) (int64, error) {
-	return toIntType[int64](varName, 64, strconv.ParseInt)
+func ToInt64(varName string, opts ...Option) (int64, error) {
+	return toIntType[int64](varName, 64, strconv.ParseInt, opts)
 }
 
 // ToInt64Slice returns the value of the requested environment variable
 // converted to a slice of int64s. An error will be returned if the
 // environment variable is not found or the conversion to
 // slice of int64s fails.
-func ToInt64Slice(varName string, separator string) ([]int64, error) {
-	return toIntSliceType[int64](varName, separator, 64, strconv.ParseInt)
+func ToInt64Slice(varName string, separator string, opts ...Option) ([]int64, error) {
+	return toIntSliceType[int64](varName, separator, 64, strconv.ParseInt, opts)
 }
 
 // ToInt64WithDefault returns the value of the requested environment
 // variable converted to an int64. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to int64 fails.
-func ToInt64WithDefault(varName string, defaultValue int64) int64 {
-	return toIntTypeWithDefault[int64](varName, defaultValue, 64, strconv.ParseInt)
+func ToInt64WithDefault(varName string, defaultValue int64, opts ...Option) int64 {
+	return toIntTypeWithDefault[int64](varName, defaultValue, 64, strconv.ParseInt, opts)
 }
 
 // ToInt64SliceWithDefault returns the value of the requested environment
 // variable converted to a slice of int64s. The default value passed as
 // the second parameter will be returned if the environment
 // variable is not found or the conversion to a slice of int64s fails.
-func ToInt64SliceWithDefault(varName string, separator string, defaultValue []int64) []int64 {
-	return toIntSliceTypeWithDefault[int64](varName, separator, defaultValue, 64, strconv.ParseInt)
+func ToInt64SliceWithDefault(varName string, separator string, defaultValue []int64, opts ...Option) []int64 {
+	return toIntSliceTypeWithDefault[int64](varName, separator, defaultValue, 64, strconv.ParseInt, opts)
+}
+
+// ToInt64Slice2D returns the value of the requested environment variable
+// converted to a nested slice of int64s. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToInt64Slice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]int64, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, intConverter[int64](64, strconv.ParseInt))
+}
+
+// ToInt64Slice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of int64s. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToInt64Slice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]int64, opts ...Option) [][]int64 {
+	value, err := ToInt64Slice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
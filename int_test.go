@@ -1,9 +1,11 @@
 package envconv_test
 
 import (
+	"os"
 	"testing"
 
 	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestToInt(t *testing.T) {
@@ -465,3 +467,73 @@ func TestToInt64SliceWithDefault(t *testing.T) {
 	}
 	runSliceWithDefaultEmptyTest[int64](t, ",", defaultValue, envconv.ToInt64SliceWithDefault)
 }
+
+func TestToIntSlice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]int
+		errorExpected bool
+	}{
+		{"TEST_INT_SLICE_2D_SHARDS", "1,2,3;4,5;6", [][]int{{1, 2, 3}, {4, 5}, {6}}, false},
+		{"TEST_INT_SLICE_2D_WHITESPACE", "1, 2; 3 ,4", [][]int{{1, 2}, {3, 4}}, false},
+		{"TEST_INT_SLICE_2D_SINGLE", "1,2,3", [][]int{{1, 2, 3}}, false},
+		{"TEST_INT_SLICE_2D_NOTANUMBER", "1,2;3,four", [][]int{}, true},
+		{"TEST_INT_SLICE_2D_EMPTY_ROW", "1,2;;3", [][]int{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToIntSlice2D)
+	}
+
+	t.Run("TEST_INT_SLICE_2D_ERROR_INDEX", func(t *testing.T) {
+		os.Setenv("TEST_INT_SLICE_2D_ERROR_INDEX", "1,2,3;4,five;6")
+		_, err := envconv.ToIntSlice2D("TEST_INT_SLICE_2D_ERROR_INDEX", ";", ",")
+		var nestedErr *envconv.NestedElementError
+		if assert.ErrorAs(t, err, &nestedErr) {
+			assert.Equal(t, 1, nestedErr.Outer, "they should be equal")
+			assert.Equal(t, 1, nestedErr.Inner, "they should be equal")
+			assert.Equal(t, "five", nestedErr.Element, "they should be equal")
+		}
+	})
+
+	t.Run("TEST_NON_EXISTANT does not exist", func(t *testing.T) {
+		v, err := envconv.ToIntSlice2D("TEST_NON_EXISTANT", ";", ",")
+		assert.Error(t, err, "there should be an error")
+		assert.Equal(t, [][]int{}, v, "they should be equal")
+	})
+}
+
+func TestToIntSlice2DWithDefault(t *testing.T) {
+	defaultValue := [][]int{{1}, {0, 5}}
+	os.Setenv("TEST_INT_SLICE_2D_WITH_DEFAULT_VALID", "1,2;3")
+	os.Setenv("TEST_INT_SLICE_2D_WITH_DEFAULT_INVALID", "1,2;three")
+
+	v := envconv.ToIntSlice2DWithDefault("TEST_INT_SLICE_2D_WITH_DEFAULT_VALID", ";", ",", defaultValue)
+	assert.Equal(t, [][]int{{1, 2}, {3}}, v, "they should be equal")
+
+	v = envconv.ToIntSlice2DWithDefault("TEST_INT_SLICE_2D_WITH_DEFAULT_INVALID", ";", ",", defaultValue)
+	assert.Equal(t, defaultValue, v, "they should be equal")
+
+	v = envconv.ToIntSlice2DWithDefault("TEST_NON_EXISTANT", ";", ",", defaultValue)
+	assert.Equal(t, defaultValue, v, "they should be equal")
+}
+
+func TestToInt8Slice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]int8
+		errorExpected bool
+	}{
+		{"TEST_INT8_SLICE_2D_VALID", "-128,0;127", [][]int8{{-128, 0}, {127}}, false},
+		{"TEST_INT8_SLICE_2D_OUT_OF_RANGE", "1,2;128", [][]int8{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToInt8Slice2D)
+	}
+
+	v := envconv.ToInt64Slice2DWithDefault("TEST_NON_EXISTANT", ";", ",", [][]int64{{1}})
+	assert.Equal(t, [][]int64{{1}}, v, "they should be equal")
+}
@@ -0,0 +1,104 @@
+package envconv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// JSONError is wrapped in the ConversionError returned by ToJSON when
+// the value of the requested environment variable is not valid JSON,
+// or does not match the requested type. Offset is the position in
+// the value at which the problem was found, or -1 if it is
+// not known.
+type JSONError struct {
+	Offset int64
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *JSONError) Error() string {
+	if e.Offset < 0 {
+		return fmt.Sprintf("invalid JSON: %v", e.Err)
+	}
+	return fmt.Sprintf("invalid JSON at offset %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying encoding/json error.
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// jsonConverter returns a conversion function that decodes a JSON
+// string into type T, reporting any failure as a JSONError.
+func jsonConverter[T any](o *options) func(string) (T, error) {
+	return func(value string) (T, error) {
+		var convertedValue T
+		if err := decodeJSON(value, &convertedValue, o); err != nil {
+			var zero T
+			return zero, err
+		}
+		return convertedValue, nil
+	}
+}
+
+// decodeJSON decodes the passed JSON string into the value pointed to
+// by ptr, reporting any failure as a JSONError.
+func decodeJSON(value string, ptr any, o *options) error {
+	decoder := json.NewDecoder(strings.NewReader(value))
+	if o.disallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(ptr); err != nil {
+		return newJSONError(value, err)
+	}
+
+	end := decoder.InputOffset()
+	if _, err := decoder.Token(); err != io.EOF {
+		if err != nil {
+			return newJSONError(value, err)
+		}
+		rest := value[end:]
+		end += int64(len(rest) - len(strings.TrimLeft(rest, " \t\r\n")))
+		return &JSONError{Offset: end, Err: errors.New("unexpected data after top-level value")}
+	}
+	return nil
+}
+
+// newJSONError wraps the passed encoding/json error in a JSONError,
+// recording the offset of the problem where it is known.
+func newJSONError(value string, err error) *JSONError {
+	jsonErr := &JSONError{Offset: -1, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		jsonErr.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		jsonErr.Offset = typeErr.Offset
+	case err == io.ErrUnexpectedEOF || err == io.EOF:
+		jsonErr.Offset = int64(len(value))
+	}
+	return jsonErr
+}
+
+// ToJSON returns the value of the requested environment variable
+// decoded as JSON into a value of type T. An error will be
+// returned if the environment variable is not found or
+// the value cannot be decoded into type T.
+func ToJSON[T any](varName string, opts ...Option) (T, error) {
+	return convert(varName, opts, jsonConverter[T](newOptions(opts)))
+}
+
+// ToJSONWithDefault returns the value of the requested environment
+// variable decoded as JSON into a value of type T. The default
+// value passed as the second parameter will be returned if the
+// environment variable is not found or the value cannot be
+// decoded into type T.
+func ToJSONWithDefault[T any](varName string, defaultValue T, opts ...Option) T {
+	value, err := ToJSON[T](varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
+}
@@ -0,0 +1,82 @@
+package envconv_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+type testRoute struct {
+	Path    string `json:"path"`
+	Backend string `json:"backend"`
+}
+
+func TestToJSON(t *testing.T) {
+	testData := []struct {
+		env            string
+		value          string
+		expected       []testRoute
+		errorExpected  bool
+		expectedOffset int64
+	}{
+		{"TEST_JSON_ROUTES", `[{"path":"/","backend":"web"},{"path":"/api","backend":"api"}]`, []testRoute{{"/", "web"}, {"/api", "api"}}, false, 0},
+		{"TEST_JSON_EMPTY_ARRAY", `[]`, []testRoute{}, false, 0},
+		{"TEST_JSON_SYNTAX", `[{"path":"/",}]`, nil, true, 14},
+		{"TEST_JSON_TYPE", `[{"path":1}]`, nil, true, 10},
+		{"TEST_JSON_TRUNCATED", `[{"path":"/"`, nil, true, 12},
+		{"TEST_JSON_TRAILING", `[] []`, nil, true, 3},
+	}
+
+	for _, td := range testData {
+		t.Run(td.env, func(t *testing.T) {
+			os.Setenv(td.env, td.value)
+			v, err := envconv.ToJSON[[]testRoute](td.env)
+			if td.errorExpected {
+				var jsonErr *envconv.JSONError
+				if assert.ErrorAs(t, err, &jsonErr) {
+					assert.Equal(t, td.expectedOffset, jsonErr.Offset, "they should be equal")
+					assert.Contains(t, err.Error(), td.env)
+				}
+			} else {
+				assert.NoError(t, err, "there should be no error")
+			}
+			assert.Equal(t, td.expected, v, "they should be equal")
+		})
+	}
+
+	t.Run("TEST_NON_EXISTANT does not exist", func(t *testing.T) {
+		v, err := envconv.ToJSON[map[string]int]("TEST_NON_EXISTANT")
+		assert.ErrorIs(t, err, envconv.ErrNotSet)
+		assert.Nil(t, v, "it should be nil")
+	})
+}
+
+func TestToJSONDisallowUnknownFields(t *testing.T) {
+	os.Setenv("TEST_JSON_UNKNOWN_FIELD", `{"path":"/","backend":"web","weight":5}`)
+
+	v, err := envconv.ToJSON[testRoute]("TEST_JSON_UNKNOWN_FIELD")
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, testRoute{"/", "web"}, v, "they should be equal")
+
+	_, err = envconv.ToJSON[testRoute]("TEST_JSON_UNKNOWN_FIELD", envconv.DisallowUnknownFields())
+	var jsonErr *envconv.JSONError
+	assert.ErrorAs(t, err, &jsonErr)
+	assert.Contains(t, err.Error(), "weight")
+}
+
+func TestToJSONWithDefault(t *testing.T) {
+	defaultValue := map[string]int{"default": 1}
+	os.Setenv("TEST_JSON_WITH_DEFAULT_VALID", `{"a":1,"b":2}`)
+	os.Setenv("TEST_JSON_WITH_DEFAULT_INVALID", `{"a":`)
+
+	v := envconv.ToJSONWithDefault("TEST_JSON_WITH_DEFAULT_VALID", defaultValue)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, v, "they should be equal")
+
+	v = envconv.ToJSONWithDefault("TEST_JSON_WITH_DEFAULT_INVALID", defaultValue)
+	assert.Equal(t, defaultValue, v, "they should be equal")
+
+	v = envconv.ToJSONWithDefault("TEST_NON_EXISTANT", defaultValue)
+	assert.Equal(t, defaultValue, v, "they should be equal")
+}
@@ -0,0 +1,19 @@
+package envconv
+
+import "fmt"
+
+// Must returns the passed value if err is nil, and panics otherwise.
+// It is intended to wrap a call to any of the conversion functions
+// in variable initialisations, such as
+//
+//	var port = envconv.Must(envconv.ToInt("PORT"))
+//
+// The panic message names the expected type along with the variable
+// and raw value reported by err. Use the Redact option to keep the
+// raw value out of the message.
+func Must[T any](value T, err error) T {
+	if err != nil {
+		panic(fmt.Sprintf("envconv: Must[%s]: %v", typeName[T](), err))
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package envconv_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMust(t *testing.T) {
+	os.Setenv("TEST_MUST_INT", "105")
+	os.Setenv("TEST_MUST_INVALID_INT", "notanumber")
+	os.Setenv("TEST_MUST_INT_SLICE", "1,0,5")
+	os.Setenv("TEST_MUST_INVALID_INT_SLICE", "80,eighty")
+	os.Setenv("TEST_MUST_DURATION", "5s")
+
+	t.Run("valid", func(t *testing.T) {
+		assert.Equal(t, 105, envconv.Must(envconv.ToInt("TEST_MUST_INT")), "they should be equal")
+		assert.Equal(t, 5*time.Second, envconv.Must(envconv.ToDuration("TEST_MUST_DURATION")), "they should be equal")
+		assert.Equal(t, []int{1, 0, 5}, envconv.Must(envconv.ToIntSlice("TEST_MUST_INT_SLICE", ",")), "they should be equal")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			`envconv: Must[int]: TEST_MUST_INVALID_INT: cannot convert "notanumber" to int: strconv.ParseInt: parsing "notanumber": invalid syntax`,
+			func() { envconv.Must(envconv.ToInt("TEST_MUST_INVALID_INT")) },
+		)
+	})
+
+	t.Run("invalid slice", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			`envconv: Must[[]int]: TEST_MUST_INVALID_INT_SLICE: element 1 "eighty": strconv.ParseInt: parsing "eighty": invalid syntax`,
+			func() { envconv.Must(envconv.ToIntSlice("TEST_MUST_INVALID_INT_SLICE", ",")) },
+		)
+	})
+
+	t.Run("redacted", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			`envconv: Must[int]: TEST_MUST_INVALID_INT: cannot convert [REDACTED] to int: strconv.ParseInt: invalid syntax`,
+			func() { envconv.Must(envconv.ToInt("TEST_MUST_INVALID_INT", envconv.Redact())) },
+		)
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		assert.PanicsWithValue(t,
+			`envconv: Must[time.Duration]: TEST_NON_EXISTANT: unknown environment variable`,
+			func() { envconv.Must(envconv.ToDuration("TEST_NON_EXISTANT")) },
+		)
+	})
+}
@@ -0,0 +1,234 @@
+package envconv
+
+// EmptyPolicy determines how an environment variable that is
+// set, but set to an empty string, is treated.
+type EmptyPolicy int
+
+const (
+	// EmptyAsValue passes the empty string on to the conversion, exactly
+	// as any other value would be. This means an empty variable will
+	// fail to convert to most types. This is the default policy.
+	EmptyAsValue EmptyPolicy = iota
+
+	// EmptyAsUnset treats an empty environment variable as if it
+	// was not set at all.
+	EmptyAsUnset
+
+	// EmptyAsError returns ErrEmpty for an empty environment variable.
+	EmptyAsError
+
+	// EmptyAsZero returns the zero value of the requested type, or an
+	// empty slice for the slice functions, for an empty
+	// environment variable.
+	EmptyAsZero
+)
+
+// Option configures how a single call loads and converts
+// the requested environment variable.
+type Option func(*options)
+
+// options holds the configuration built from the Options passed
+// to a single call.
+type options struct {
+	empty          EmptyPolicy
+	strict         bool
+	dropEmpty      bool
+	rejectTrailing bool
+	quoted         bool
+	lenient        bool
+	redact         bool
+	required       bool
+	aliases        []string
+	fileSecrets    bool
+	onDeprecated   func(alias string, varName string)
+	report         *Report
+	source         Source
+	target         Setter
+	suggestPrefix  string
+	prefix         string
+
+	disallowUnknownFields bool
+}
+
+// newOptions returns the options built by applying each of the
+// passed Options in order.
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithEmpty returns an Option that sets the policy used when the
+// requested environment variable is set to an empty string. Passed
+// to NewLoader, it sets the policy for every field of a struct,
+// and the empty tag sets it for a single field.
+func WithEmpty(policy EmptyPolicy) Option {
+	return func(o *options) {
+		o.empty = policy
+	}
+}
+
+// Strict returns an Option that stops the slice functions from trimming
+// whitespace from the value and its elements, so "1, 2" will fail
+// to convert to a slice of ints.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// DropEmpty returns an Option that makes the slice functions discard
+// empty elements, rather than passing them on to the conversion.
+func DropEmpty() Option {
+	return func(o *options) {
+		o.dropEmpty = true
+	}
+}
+
+// RejectTrailingSeparator returns an Option that makes the slice
+// functions return ErrTrailingSeparator when the value ends
+// with the separator.
+func RejectTrailingSeparator() Option {
+	return func(o *options) {
+		o.rejectTrailing = true
+	}
+}
+
+// Quoted returns an Option that makes the slice functions split their
+// value in a CSV-like way. Elements may be wrapped in double quotes,
+// within which the separator has no special meaning, and a
+// backslash escapes the character that follows it.
+// Whitespace inside quotes is never trimmed.
+func Quoted() Option {
+	return func(o *options) {
+		o.quoted = true
+	}
+}
+
+// Lenient returns an Option that makes the slice functions skip any
+// element that fails to convert, rather than failing outright. The
+// successfully converted elements are returned along with an
+// ElementErrors error describing each skipped element. The
+// WithDefault functions return the converted elements,
+// rather than the default value, in this case.
+func Lenient() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+// DisallowUnknownFields returns an Option that makes ToJSON return an
+// error when a JSON object contains a key that does not match any
+// exported field of the destination struct.
+func DisallowUnknownFields() Option {
+	return func(o *options) {
+		o.disallowUnknownFields = true
+	}
+}
+
+// Redact returns an Option that stops the raw value of the requested
+// environment variable from appearing in any error returned by
+// the call, in the panic message produced by Must, or in
+// the usage output of a VarSet. The secret struct tag sets it
+// for a single field decoded with Decode.
+func Redact() Option {
+	return func(o *options) {
+		o.redact = true
+	}
+}
+
+// Aliases returns an Option that makes the call fall back to each of
+// the passed environment variable names, in order, when the requested
+// variable is not set. This allows a variable to be renamed while
+// still accepting its old name. ErrConflict is returned if more than
+// one of the names is set, and their values differ. The aliases struct
+// tag sets it for a single field decoded with Decode.
+func Aliases(names ...string) Option {
+	return func(o *options) {
+		o.aliases = append(o.aliases, names...)
+	}
+}
+
+// FileSecrets returns an Option that makes the call fall back to reading
+// the requested variable from a file, as is common for secrets mounted
+// into containers. When neither the variable nor any of its aliases
+// is set, the first of them that is set with a "_FILE" suffix, such
+// as DB_PASSWORD_FILE, names the file to read. A single trailing
+// newline is removed from its contents, which are then treated as
+// the value of the variable.
+func FileSecrets() Option {
+	return func(o *options) {
+		o.fileSecrets = true
+	}
+}
+
+// OnDeprecated returns an Option that sets a function to be called
+// whenever one of the names passed to Aliases supplies the value,
+// rather than the requested environment variable itself. This
+// is typically used to log a deprecation warning.
+func OnDeprecated(fn func(alias string, varName string)) Option {
+	return func(o *options) {
+		o.onDeprecated = fn
+	}
+}
+
+// Required returns an Option that makes a variable declared in a VarSet
+// mandatory, so that Parse returns ErrNotSet if it is not set. The
+// To* functions always return an error for a missing variable,
+// and the WithDefault functions never do, so this Option has
+// no effect on them.
+func Required() Option {
+	return func(o *options) {
+		o.required = true
+	}
+}
+
+// Record returns an Option that records where the value of the
+// requested environment variable came from in the passed Report.
+func Record(r *Report) Option {
+	return func(o *options) {
+		o.report = r
+	}
+}
+
+// FromSource returns an Option that makes the call load its variable,
+// and any aliases, from the passed Source rather than from the
+// process environment.
+func FromSource(src Source) Option {
+	return func(o *options) {
+		o.source = src
+	}
+}
+
+// SuggestPrefix returns an Option that restricts the similar names
+// suggested when the requested variable is not set to those that
+// start with prefix, typically the prefix shared by all of an
+// application's variables. Without it, or with an empty prefix,
+// suggestions share the prefix of the requested name up to and
+// including its first underscore. Prefixes are compared
+// without regard to case.
+func SuggestPrefix(prefix string) Option {
+	return func(o *options) {
+		o.suggestPrefix = prefix
+	}
+}
+
+// Prefix returns an Option that adds prefix to the name of every
+// variable declared from a struct by Decode, Loader and
+// VarSet.Struct. It has no effect on the other functions.
+func Prefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// Into returns an Option that makes the Set functions write to the
+// passed Setter, such as a MutableEnv, rather than to the
+// process environment.
+func Into(dst Setter) Option {
+	return func(o *options) {
+		o.target = dst
+	}
+}
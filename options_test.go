@@ -0,0 +1,172 @@
+package envconv_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithEmpty(t *testing.T) {
+	os.Setenv("TEST_EMPTY_POLICY", "")
+
+	testData := []struct {
+		name          string
+		policy        envconv.EmptyPolicy
+		expectedError error
+	}{
+		{"EmptyAsUnset", envconv.EmptyAsUnset, envconv.ErrNotSet},
+		{"EmptyAsError", envconv.EmptyAsError, envconv.ErrEmpty},
+		{"EmptyAsZero", envconv.EmptyAsZero, nil},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			opt := envconv.WithEmpty(td.policy)
+
+			i, err := envconv.ToInt("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, 0, i, "they should be equal")
+
+			u, err := envconv.ToUint16("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, uint16(0), u, "they should be equal")
+
+			f, err := envconv.ToFloat64("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, float64(0), f, "they should be equal")
+
+			b, err := envconv.ToBool("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, false, b, "they should be equal")
+
+			d, err := envconv.ToDuration("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, time.Duration(0), d, "they should be equal")
+
+			s, err := envconv.ToString("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, "", s, "they should be equal")
+
+			bs, err := envconv.ToByteSlice("TEST_EMPTY_POLICY", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, []byte{}, bs, "they should be equal")
+
+			is, err := envconv.ToIntSlice("TEST_EMPTY_POLICY", ",", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, []int{}, is, "they should be equal")
+
+			ss, err := envconv.ToStringSlice("TEST_EMPTY_POLICY", ",", opt)
+			assert.ErrorIs(t, err, td.expectedError)
+			assert.Equal(t, []string{}, ss, "they should be equal")
+		})
+	}
+
+	t.Run("EmptyAsValue", func(t *testing.T) {
+		_, err := envconv.ToInt("TEST_EMPTY_POLICY", envconv.WithEmpty(envconv.EmptyAsValue))
+		assert.Error(t, err, "there should be an error")
+
+		s, err := envconv.ToString("TEST_EMPTY_POLICY", envconv.WithEmpty(envconv.EmptyAsValue))
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, "", s, "they should be equal")
+
+		ss, err := envconv.ToStringSlice("TEST_EMPTY_POLICY", ",")
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []string{""}, ss, "they should be equal")
+	})
+}
+
+func TestWithEmptyWithDefault(t *testing.T) {
+	os.Setenv("TEST_EMPTY_POLICY_WITH_DEFAULT", "")
+
+	testData := []struct {
+		name           string
+		policy         envconv.EmptyPolicy
+		expectedInt    int
+		expectedString string
+		expectedSlice  []int
+	}{
+		{"EmptyAsValue", envconv.EmptyAsValue, 105, "", []int{1, 0, 5}},
+		{"EmptyAsUnset", envconv.EmptyAsUnset, 105, "default", []int{1, 0, 5}},
+		{"EmptyAsError", envconv.EmptyAsError, 105, "default", []int{1, 0, 5}},
+		{"EmptyAsZero", envconv.EmptyAsZero, 0, "", []int{}},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			opt := envconv.WithEmpty(td.policy)
+
+			i := envconv.ToIntWithDefault("TEST_EMPTY_POLICY_WITH_DEFAULT", 105, opt)
+			assert.Equal(t, td.expectedInt, i, "they should be equal")
+
+			s := envconv.ToStringWithDefault("TEST_EMPTY_POLICY_WITH_DEFAULT", "default", opt)
+			assert.Equal(t, td.expectedString, s, "they should be equal")
+
+			is := envconv.ToIntSliceWithDefault("TEST_EMPTY_POLICY_WITH_DEFAULT", ",", []int{1, 0, 5}, opt)
+			assert.Equal(t, td.expectedSlice, is, "they should be equal")
+		})
+	}
+}
+
+func TestAliases(t *testing.T) {
+	testData := []struct {
+		name          string
+		env           map[string]string
+		expected      string
+		expectedAlias string
+		expectedError error
+	}{
+		{"primary only", map[string]string{"TEST_ALIAS_PRIMARY": "db1"}, "db1", "", nil},
+		{"alias only", map[string]string{"TEST_ALIAS_OLD": "db2"}, "db2", "TEST_ALIAS_OLD", nil},
+		{"second alias", map[string]string{"TEST_ALIAS_OLDER": "db3"}, "db3", "TEST_ALIAS_OLDER", nil},
+		{"same values", map[string]string{"TEST_ALIAS_PRIMARY": "db4", "TEST_ALIAS_OLD": "db4"}, "db4", "", nil},
+		{"conflict", map[string]string{"TEST_ALIAS_PRIMARY": "db5", "TEST_ALIAS_OLD": "db6"}, "", "", envconv.ErrConflict},
+		{"alias conflict", map[string]string{"TEST_ALIAS_OLD": "db7", "TEST_ALIAS_OLDER": "db8"}, "", "", envconv.ErrConflict},
+		{"none", map[string]string{}, "", "", envconv.ErrNotSet},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			for _, name := range []string{"TEST_ALIAS_PRIMARY", "TEST_ALIAS_OLD", "TEST_ALIAS_OLDER"} {
+				os.Unsetenv(name)
+			}
+			for name, value := range td.env {
+				os.Setenv(name, value)
+			}
+
+			var deprecatedAlias, deprecatedPrimary string
+			v, err := envconv.ToString("TEST_ALIAS_PRIMARY",
+				envconv.Aliases("TEST_ALIAS_OLD", "TEST_ALIAS_OLDER"),
+				envconv.OnDeprecated(func(alias string, varName string) {
+					deprecatedAlias, deprecatedPrimary = alias, varName
+				}),
+			)
+			if td.expectedError != nil {
+				assert.ErrorIs(t, err, td.expectedError)
+			} else {
+				assert.NoError(t, err, "there should be no error")
+			}
+			assert.Equal(t, td.expected, v, "they should be equal")
+			assert.Equal(t, td.expectedAlias, deprecatedAlias, "they should be equal")
+			if td.expectedAlias != "" {
+				assert.Equal(t, "TEST_ALIAS_PRIMARY", deprecatedPrimary, "they should be equal")
+			}
+		})
+	}
+
+	t.Run("empty as unset", func(t *testing.T) {
+		os.Setenv("TEST_ALIAS_EMPTY_PRIMARY", "")
+		os.Setenv("TEST_ALIAS_EMPTY_OLD", "8080")
+		v, err := envconv.ToInt("TEST_ALIAS_EMPTY_PRIMARY", envconv.Aliases("TEST_ALIAS_EMPTY_OLD"), envconv.WithEmpty(envconv.EmptyAsUnset))
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, 8080, v, "they should be equal")
+	})
+
+	t.Run("slice with default", func(t *testing.T) {
+		os.Setenv("TEST_ALIAS_SLICE_OLD", "1,2")
+		v := envconv.ToIntSliceWithDefault("TEST_ALIAS_SLICE_PRIMARY", ",", []int{3}, envconv.Aliases("TEST_ALIAS_SLICE_OLD"))
+		assert.Equal(t, []int{1, 2}, v, "they should be equal")
+	})
+}
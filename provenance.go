@@ -0,0 +1,190 @@
+package envconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// Origin identifies where the value of an environment variable
+// came from.
+type Origin string
+
+const (
+	// OriginEnvironment means the value was read from the
+	// process environment.
+	OriginEnvironment Origin = "environment"
+
+	// OriginSource means the value was read from the Source passed
+	// with the FromSource option, such as a FileSource.
+	OriginSource Origin = "source"
+
+	// OriginFile means the value was read from the file named by
+	// the variable with a "_FILE" suffix, with the FileSecrets
+	// option. Location holds the path of the file.
+	OriginFile Origin = "file"
+
+	// OriginDefault means the default value passed to a WithDefault
+	// function, or declared in a VarSet, was used.
+	OriginDefault Origin = "default"
+
+	// OriginZero means the variable was empty and the EmptyAsZero
+	// policy returned the zero value of its type.
+	OriginZero Origin = "zero"
+
+	// OriginNone means no value could be resolved, and an error
+	// was returned instead.
+	OriginNone Origin = "none"
+)
+
+// Provenance records how the value of a single environment variable
+// was resolved. From names the variable that supplied the value,
+// which differs from Name when an alias was used. Location
+// describes where a Source defined it, such as the file
+// and line of a dotenv file. Value is the raw value,
+// or the default value as text, and is redacted
+// for a variable that uses the Redact option.
+type Provenance struct {
+	Name     string `json:"name"`
+	Origin   Origin `json:"origin"`
+	From     string `json:"from,omitempty"`
+	Location string `json:"location,omitempty"`
+	Value    string `json:"value"`
+	Secret   bool   `json:"secret,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// Report collects the Provenance of every environment variable loaded
+// with the Record option. It is safe for concurrent use. When the
+// same variable is loaded more than once, the latest resolution
+// is kept. The zero value is an empty Report ready to use.
+type Report struct {
+	mu      sync.Mutex
+	entries map[string]Provenance
+}
+
+// NewReport returns a new, empty Report.
+func NewReport() *Report {
+	return &Report{entries: map[string]Provenance{}}
+}
+
+// add records the passed Provenance, replacing any earlier entry
+// for the same variable.
+func (r *Report) add(p Provenance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.entries == nil {
+		r.entries = map[string]Provenance{}
+	}
+	r.entries[p.Name] = p
+}
+
+// Lookup returns the Provenance recorded for the named variable,
+// and whether there is one.
+func (r *Report) Lookup(name string) (Provenance, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.entries[name]
+	return p, ok
+}
+
+// Entries returns every recorded Provenance, in lexicographical
+// order of name.
+func (r *Report) Entries() []Provenance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]Provenance, 0, len(r.entries))
+	for _, p := range r.entries {
+		entries = append(entries, p)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// WriteTo writes the report to w as a plain text table.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tORIGIN\tFROM\tLOCATION\tVALUE\tERROR")
+	for _, p := range r.Entries() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%q\t%s\n", p.Name, p.Origin, p.From, p.Location, p.Value, p.Error)
+	}
+	tw.Flush()
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// String returns the report as a plain text table.
+func (r *Report) String() string {
+	var b strings.Builder
+	r.WriteTo(&b)
+	return b.String()
+}
+
+// MarshalJSON implements json.Marshaler, encoding the report as an
+// array of its entries.
+func (r *Report) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.Entries())
+}
+
+// record adds a Provenance to the report in the passed options, if
+// there is one. The value is redacted if the Redact option is
+// in use, and err, if not nil, is recorded as the reason. As
+// with recordError, an error holding the raw value has
+// already been redacted.
+func record(varName string, origin Origin, from string, value string, err error, o *options) {
+	if o.report == nil {
+		return
+	}
+	p := Provenance{Name: varName, Origin: origin, From: from, Value: value, Secret: o.redact}
+	if l, ok := o.source.(locator); ok && origin == OriginSource {
+		p.Location = l.Locate(from)
+	}
+	if origin == OriginFile {
+		p.Location, _ = lookupEnv(from, o)
+	}
+	if from == varName {
+		p.From = ""
+	}
+	if o.redact && value != "" {
+		p.Value = redacted
+	}
+	if err != nil {
+		p.Error = errorMessage(err)
+	}
+	o.report.add(p)
+}
+
+// errorMessage returns the message of the passed error for a Report,
+// leaving out the suggestions of a NotSetError, which would have
+// to be found by searching the whole Source.
+func errorMessage(err error) string {
+	if notSet, ok := err.(*NotSetError); ok {
+		return notSet.message()
+	}
+	return err.Error()
+}
+
+// recordError sets the error of the Provenance already recorded for
+// the named variable in the report in the passed options, if there
+// is one. Errors created after the raw value has been loaded are
+// redacted before they reach here.
+func recordError(varName string, err error, o *options) {
+	if o.report == nil {
+		return
+	}
+	o.report.mu.Lock()
+	defer o.report.mu.Unlock()
+	if o.report.entries == nil {
+		o.report.entries = map[string]Provenance{}
+	}
+	p := o.report.entries[varName]
+	p.Error = errorMessage(err)
+	o.report.entries[varName] = p
+}
@@ -0,0 +1,167 @@
+package envconv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	os.Setenv("TEST_RECORD_PORT", "9090")
+	os.Setenv("TEST_RECORD_OLD_HOST", "db.internal")
+	os.Setenv("TEST_RECORD_INVALID", "eighty")
+	os.Setenv("TEST_RECORD_EMPTY", "")
+	os.Setenv("TEST_RECORD_PASSWORD", "hunter2")
+	os.Setenv("TEST_RECORD_SLICE", "1,eighty")
+	os.Unsetenv("TEST_RECORD_HOST")
+	os.Unsetenv("TEST_RECORD_MISSING")
+
+	r := envconv.NewReport()
+	record := envconv.Record(r)
+
+	envconv.ToInt("TEST_RECORD_PORT", record)
+	envconv.ToString("TEST_RECORD_HOST", record, envconv.Aliases("TEST_RECORD_OLD_HOST"))
+	envconv.ToIntWithDefault("TEST_RECORD_INVALID", 80, record)
+	envconv.ToIntWithDefault("TEST_RECORD_MISSING", 8080, record)
+	envconv.ToInt("TEST_RECORD_EMPTY", record, envconv.WithEmpty(envconv.EmptyAsZero))
+	envconv.ToSecret("TEST_RECORD_PASSWORD", record)
+	envconv.ToIntSlice("TEST_RECORD_SLICE", ",", record, envconv.Redact())
+
+	assert.Equal(t, []envconv.Provenance{
+		{Name: "TEST_RECORD_EMPTY", Origin: envconv.OriginZero},
+		{Name: "TEST_RECORD_HOST", Origin: envconv.OriginEnvironment, From: "TEST_RECORD_OLD_HOST", Value: "db.internal"},
+		{Name: "TEST_RECORD_INVALID", Origin: envconv.OriginDefault, Value: "80", Error: `TEST_RECORD_INVALID: cannot convert "eighty" to int: strconv.ParseInt: parsing "eighty": invalid syntax`},
+		{Name: "TEST_RECORD_MISSING", Origin: envconv.OriginDefault, Value: "8080", Error: "TEST_RECORD_MISSING: unknown environment variable"},
+		{Name: "TEST_RECORD_PASSWORD", Origin: envconv.OriginEnvironment, Value: "[REDACTED]", Secret: true},
+		{Name: "TEST_RECORD_PORT", Origin: envconv.OriginEnvironment, Value: "9090"},
+		{Name: "TEST_RECORD_SLICE", Origin: envconv.OriginEnvironment, Value: "[REDACTED]", Secret: true, Error: "TEST_RECORD_SLICE: element 1 [REDACTED]: strconv.ParseInt: invalid syntax"},
+	}, r.Entries(), "they should be equal")
+
+	p, ok := r.Lookup("TEST_RECORD_PORT")
+	assert.True(t, ok, "it should be found")
+	assert.Equal(t, "9090", p.Value, "they should be equal")
+
+	t.Run("output", func(t *testing.T) {
+		assert.NotContains(t, r.String(), "hunter2")
+		assert.Contains(t, r.String(), "TEST_RECORD_HOST")
+
+		b, err := json.Marshal(r)
+		assert.NoError(t, err, "there should be no error")
+		assert.NotContains(t, string(b), "hunter2")
+		assert.Contains(t, string(b), `{"name":"TEST_RECORD_PORT","origin":"environment","value":"9090"}`)
+	})
+}
+
+func TestRecordVarSet(t *testing.T) {
+	os.Setenv("TEST_RECORD_VARSET_PORT", "9090")
+	os.Unsetenv("TEST_RECORD_VARSET_HOST")
+
+	r := envconv.NewReport()
+	s := envconv.NewVarSet("test")
+	s.Int("TEST_RECORD_VARSET_PORT", 8080, "", envconv.Record(r))
+	s.String("TEST_RECORD_VARSET_HOST", "localhost", "", envconv.Record(r))
+	assert.NoError(t, s.Parse(), "there should be no error")
+
+	var b bytes.Buffer
+	_, err := r.WriteTo(&b)
+	assert.NoError(t, err, "there should be no error")
+	assert.Regexp(t, `TEST_RECORD_VARSET_HOST\s+default\s+"localhost"`, b.String())
+	assert.Regexp(t, `TEST_RECORD_VARSET_PORT\s+environment\s+"9090"`, b.String())
+}
+
+func TestRecordZeroReport(t *testing.T) {
+	var r envconv.Report
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{"PORT": "eighty"}))
+	envconv.ToInt("PORT", src, envconv.Record(&r))
+	p, ok := r.Lookup("PORT")
+	assert.True(t, ok, "the zero Report should be ready to use")
+	assert.NotEmpty(t, p.Error, "the error should be recorded")
+}
+
+func TestFileSecrets(t *testing.T) {
+	dir := t.TempDir()
+	password := writeFile(t, filepath.Join(dir, "password"), "hunter2\n")
+	empty := writeFile(t, filepath.Join(dir, "empty"), "")
+
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{
+		"PASSWORD_FILE":  password,
+		"OLD_TOKEN_FILE": password,
+		"HOST":           "db.internal",
+		"HOST_FILE":      password,
+		"EMPTY_FILE":     empty,
+		"MISSING_FILE":   filepath.Join(dir, "missing"),
+		"UNUSED_FILE":    password,
+	}))
+
+	r := envconv.NewReport()
+	opts := []envconv.Option{src, envconv.FileSecrets(), envconv.Record(r)}
+
+	value, err := envconv.ToString("PASSWORD", opts...)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "hunter2", value, "the trailing newline should be removed")
+	p, _ := r.Lookup("PASSWORD")
+	assert.Equal(t, envconv.Provenance{Name: "PASSWORD", Origin: envconv.OriginFile, From: "PASSWORD_FILE", Location: password, Value: "hunter2"}, p, "they should be equal")
+
+	var deprecated string
+	value, err = envconv.ToString("TOKEN", append(opts, envconv.Aliases("OLD_TOKEN"), envconv.Redact(), envconv.OnDeprecated(func(alias string, varName string) {
+		deprecated = alias
+	}))...)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "hunter2", value, "an alias should be read from its file")
+	assert.Equal(t, "OLD_TOKEN", deprecated, "they should be equal")
+	p, _ = r.Lookup("TOKEN")
+	assert.Equal(t, envconv.Provenance{Name: "TOKEN", Origin: envconv.OriginFile, From: "OLD_TOKEN_FILE", Location: password, Value: "[REDACTED]", Secret: true}, p, "they should be equal")
+
+	value, err = envconv.ToString("HOST", opts...)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "db.internal", value, "the variable should take precedence over its file")
+
+	_, err = envconv.ToString("EMPTY", append(opts, envconv.WithEmpty(envconv.EmptyAsUnset))...)
+	assert.ErrorIs(t, err, envconv.ErrNotSet, "an empty file should be unset with EmptyAsUnset")
+	_, err = envconv.ToString("MISSING", opts...)
+	assert.ErrorIs(t, err, os.ErrNotExist, "the error should wrap os.ErrNotExist")
+	p, _ = r.Lookup("MISSING")
+	assert.Equal(t, envconv.OriginNone, p.Origin, "they should be equal")
+	_, err = envconv.ToString("PASSWORD", src)
+	assert.ErrorIs(t, err, envconv.ErrNotSet, "files should only be read with FileSecrets")
+
+	s := envconv.NewVarSet("test")
+	s.String("PASSWORD", "", "", envconv.FileSecrets())
+	assert.NoError(t, s.CheckUnknown(envconv.NewEnv(map[string]string{"PASSWORD_FILE": password}), ""), "the file variable should be known")
+}
+
+func TestRecordVarSetFailure(t *testing.T) {
+	r := envconv.NewReport()
+	s := envconv.NewVarSet("test")
+	port := s.Int("PORT", 8080, "", envconv.Record(r))
+
+	assert.Error(t, s.ParseFrom(envconv.NewEnv(map[string]string{"PORT": "eighty"})), "there should be an error")
+	p, _ := r.Lookup("PORT")
+	assert.Equal(t, envconv.OriginDefault, p.Origin, "a failed load that keeps the default should be recorded as the default")
+	assert.Equal(t, "8080", p.Value, "they should be equal")
+	assert.Contains(t, p.Error, "eighty", "the error should be recorded")
+
+	assert.NoError(t, s.ParseFrom(envconv.NewEnv(map[string]string{"PORT": "9090"})), "there should be no error")
+	assert.Error(t, s.ParseFrom(envconv.NewEnv(map[string]string{"PORT": "eighty"})), "there should be an error")
+	p, _ = r.Lookup("PORT")
+	assert.Equal(t, 9090, *port, "the loaded value should be kept")
+	assert.NotEqual(t, envconv.OriginDefault, p.Origin, "a failed load that keeps a loaded value should not be recorded as the default")
+}
+
+func TestRecordRedactedDefault(t *testing.T) {
+	r := envconv.NewReport()
+	s := envconv.NewVarSet("test")
+	s.String("TOKEN", "s3cr3t", "", envconv.Redact(), envconv.Record(r))
+	envconv.ToIntWithDefault("TOKEN_COUNT", 3, envconv.Redact(), envconv.Record(r), envconv.FromSource(envconv.NewEnv(nil)))
+
+	assert.NoError(t, s.ParseFrom(envconv.NewEnv(nil)), "there should be no error")
+	p, _ := r.Lookup("TOKEN")
+	assert.Equal(t, envconv.Provenance{Name: "TOKEN", Origin: envconv.OriginDefault, Value: "[REDACTED]", Secret: true, Error: "TOKEN: unknown environment variable"}, p, "they should be equal")
+	p, _ = r.Lookup("TOKEN_COUNT")
+	assert.Equal(t, "TOKEN_COUNT: unknown environment variable", p.Error, "they should be equal")
+}
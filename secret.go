@@ -0,0 +1,111 @@
+package envconv
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// Secret holds a sensitive value, such as a password or token, that
+// redacts itself wherever it would otherwise be printed, logged or
+// encoded. The value is only available through Reveal.
+//
+// The value is held behind a pointer so that it is not printed even
+// when a Secret is an unexported field of a struct, where fmt
+// cannot call its methods.
+type Secret struct {
+	value *string
+}
+
+// NewSecret returns a Secret holding the passed value.
+func NewSecret(value string) Secret {
+	return Secret{value: &value}
+}
+
+// Reveal returns the value held by the Secret.
+func (s Secret) Reveal() string {
+	if s.value == nil {
+		return ""
+	}
+	return *s.value
+}
+
+// String implements fmt.Stringer, returning a redacted placeholder.
+func (s Secret) String() string {
+	return redacted
+}
+
+// GoString implements fmt.GoStringer, returning a redacted placeholder.
+func (s Secret) GoString() string {
+	return "envconv.Secret(" + redacted + ")"
+}
+
+// Format implements fmt.Formatter, so that every verb prints a
+// redacted placeholder.
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		fmt.Fprint(f, s.GoString())
+	case verb == 'q':
+		fmt.Fprint(f, strconv.Quote(redacted))
+	default:
+		fmt.Fprint(f, redacted)
+	}
+}
+
+// MarshalJSON implements json.Marshaler, encoding a redacted placeholder.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(redacted)), nil
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding a redacted
+// placeholder.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// LogValue implements slog.LogValuer, logging a redacted placeholder.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
+
+// convertSecret converts the passed string to a Secret.
+func convertSecret(value string) (Secret, error) {
+	return NewSecret(value), nil
+}
+
+// ToSecret returns the value of the requested environment variable
+// as a Secret. An error will be returned if the environment variable
+// is not found. The Redact option is always applied, so the
+// value never appears in an error.
+func ToSecret(varName string, opts ...Option) (Secret, error) {
+	return convert(varName, append(opts[:len(opts):len(opts)], Redact()), convertSecret)
+}
+
+// ToSecretWithDefault returns the value of the requested environment
+// variable as a Secret. The default value passed as the second
+// parameter will be returned if the environment variable
+// is not found.
+func ToSecretWithDefault(varName string, defaultValue Secret, opts ...Option) Secret {
+	opts = append(opts[:len(opts):len(opts)], Redact())
+	value, err := ToSecret(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
+}
+
+// Secret declares a Secret environment variable with the passed name
+// and usage string. The Redact option is always applied, so the
+// variable is marked as secret in usage output. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Secret(name string, usage string, opts ...Option) *Secret {
+	return declare(s, name, Secret{}, usage, ToSecret, formatSecret, formatter[Secret](""), append(opts[:len(opts):len(opts)], Redact()))
+}
+
+// formatSecret formats a Secret as text for a Var, which is empty
+// if the Secret has not been set.
+func formatSecret(s Secret) string {
+	if s.value == nil {
+		return ""
+	}
+	return redacted
+}
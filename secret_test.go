@@ -0,0 +1,91 @@
+package envconv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToSecret(t *testing.T) {
+	os.Setenv("TEST_SECRET_TOKEN", "hunter2")
+
+	s, err := envconv.ToSecret("TEST_SECRET_TOKEN")
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, "hunter2", s.Reveal(), "they should be equal")
+
+	_, err = envconv.ToSecret("TEST_NON_EXISTANT")
+	assert.ErrorIs(t, err, envconv.ErrNotSet)
+
+	d := envconv.ToSecretWithDefault("TEST_NON_EXISTANT", envconv.NewSecret("default"))
+	assert.Equal(t, "default", d.Reveal(), "they should be equal")
+
+	assert.Equal(t, "", envconv.Secret{}.Reveal(), "they should be equal")
+}
+
+func TestSecretRedacts(t *testing.T) {
+	s := envconv.NewSecret("hunter2")
+	config := struct {
+		User     string
+		Password envconv.Secret
+		token    envconv.Secret
+	}{"admin", s, s}
+
+	testData := []struct {
+		name     string
+		output   string
+		expected string
+	}{
+		{"%v", fmt.Sprintf("%v", s), "[REDACTED]"},
+		{"%s", fmt.Sprintf("%s", s), "[REDACTED]"},
+		{"%q", fmt.Sprintf("%q", s), `"[REDACTED]"`},
+		{"%x", fmt.Sprintf("%x", s), "[REDACTED]"},
+		{"%#v", fmt.Sprintf("%#v", s), "envconv.Secret([REDACTED])"},
+		{"String", s.String(), "[REDACTED]"},
+		{"struct %+v", fmt.Sprintf("%+v", config.Password), "[REDACTED]"},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			assert.Equal(t, td.expected, td.output, "they should be equal")
+		})
+	}
+
+	t.Run("struct", func(t *testing.T) {
+		for _, format := range []string{"%v", "%+v", "%#v"} {
+			assert.NotContains(t, fmt.Sprintf(format, config), "hunter2")
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(config)
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, `{"User":"admin","Password":"[REDACTED]"}`, string(b), "they should be equal")
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		var b bytes.Buffer
+		slog.New(slog.NewTextHandler(&b, nil)).Info("config", "password", s)
+		assert.Contains(t, b.String(), "password=[REDACTED]")
+		assert.NotContains(t, b.String(), "hunter2")
+	})
+}
+
+func TestVarSetSecret(t *testing.T) {
+	os.Setenv("TEST_VARSET_SECRET", "hunter2")
+
+	s := envconv.NewVarSet("test")
+	token := s.Secret("TEST_VARSET_SECRET", "api token")
+	assert.NoError(t, s.Parse(), "there should be no error")
+	assert.Equal(t, "hunter2", token.Reveal(), "they should be equal")
+
+	var b bytes.Buffer
+	assert.NoError(t, s.WriteUsage(&b, envconv.UsageJSON), "there should be no error")
+	assert.NotContains(t, b.String(), "hunter2")
+	assert.True(t, s.Lookup("TEST_VARSET_SECRET").Secret, "it should be secret")
+}
@@ -0,0 +1,219 @@
+package envconv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrTrailingSeparator is returned by the slice functions when the
+	// RejectTrailingSeparator option is used and the value of the
+	// environment variable ends with the separator.
+	ErrTrailingSeparator = errors.New("trailing separator in environment variable")
+
+	// ErrUnterminatedQuote is returned by the slice functions when the
+	// Quoted option is used and the value of the environment
+	// variable contains an unterminated double quote.
+	ErrUnterminatedQuote = errors.New("unterminated quote in environment variable")
+)
+
+// ElementError is returned by the slice functions when a single
+// element fails to convert. It records the name of the environment
+// variable, along with the position and raw value of the element.
+type ElementError struct {
+	VarName string
+	Index   int
+	Element string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("%s: element %d %s: %v", e.VarName, e.Index, quoteValue(e.Element), e.Err)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// NestedElementError is returned by the nested slice functions when
+// a single element fails to convert. It records the position of
+// the element within both the outer and the inner slice.
+type NestedElementError struct {
+	VarName string
+	Outer   int
+	Inner   int
+	Element string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *NestedElementError) Error() string {
+	return fmt.Sprintf("%s: element [%d][%d] %s: %v", e.VarName, e.Outer, e.Inner, quoteValue(e.Element), e.Err)
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *NestedElementError) Unwrap() error {
+	return e.Err
+}
+
+// ElementErrors is returned by the slice functions when the Lenient
+// option is used and one or more elements fail to convert. Each
+// error is either an *ElementError or a *NestedElementError.
+type ElementErrors []error
+
+// Error implements the error interface.
+func (e ElementErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual element errors.
+func (e ElementErrors) Unwrap() []error {
+	return e
+}
+
+// quoteReplacer escapes the characters that have a special meaning
+// inside a quoted element.
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// splitValue splits the passed value by the passed separator, applying
+// the slice options. Unless the Strict option is used, each element
+// is trimmed of leading and trailing whitespace before being
+// returned.
+func splitValue(value string, separator string, o *options) ([]string, error) {
+	elements, err := splitRaw(value, separator, o)
+	if err != nil {
+		return nil, err
+	}
+	if o.quoted {
+		for i, e := range elements {
+			elements[i] = unquote(e)
+		}
+	}
+	return elements, nil
+}
+
+// splitRaw splits the passed value in the same way as splitValue, but
+// leaves any quotes and escapes in place. This allows each element
+// to be split a second time, as the nested slice functions do.
+func splitRaw(value string, separator string, o *options) ([]string, error) {
+	var elements []string
+	if o.quoted {
+		var err error
+		if elements, err = splitQuoted(value, separator); err != nil {
+			return nil, err
+		}
+	} else {
+		elements = strings.Split(value, separator)
+	}
+
+	if !o.strict {
+		for i, e := range elements {
+			elements[i] = strings.TrimSpace(e)
+		}
+	}
+	if o.rejectTrailing && len(elements) > 1 && elements[len(elements)-1] == "" {
+		return nil, ErrTrailingSeparator
+	}
+
+	result := make([]string, 0, len(elements))
+	for _, e := range elements {
+		if o.dropEmpty && e == "" {
+			continue
+		}
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// splitQuoted splits the passed value by the passed separator, ignoring
+// any separator that is escaped with a backslash or that appears
+// between double quotes. The quotes and escapes are left in
+// place, to be removed by unquote once the element has
+// been trimmed.
+func splitQuoted(value string, separator string) ([]string, error) {
+	var elements []string
+	var current strings.Builder
+	inQuotes := false
+
+	for i := 0; i < len(value); {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			current.WriteString(value[i : i+2])
+			i += 2
+		case value[i] == '"':
+			inQuotes = !inQuotes
+			current.WriteByte('"')
+			i++
+		case !inQuotes && separator != "" && strings.HasPrefix(value[i:], separator):
+			elements = append(elements, current.String())
+			current.Reset()
+			i += len(separator)
+		default:
+			current.WriteByte(value[i])
+			i++
+		}
+	}
+
+	if inQuotes {
+		return nil, ErrUnterminatedQuote
+	}
+	return append(elements, current.String()), nil
+}
+
+// unquote removes the double quotes and backslash escapes from
+// an element split by splitQuoted.
+func unquote(element string) string {
+	if !strings.ContainsAny(element, `"\`) {
+		return element
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(element); i++ {
+		switch {
+		case element[i] == '\\' && i+1 < len(element):
+			i++
+			b.WriteByte(element[i])
+		case element[i] == '"':
+		default:
+			b.WriteByte(element[i])
+		}
+	}
+	return b.String()
+}
+
+// quote returns the passed element wrapped in double quotes, if it
+// would not otherwise survive being split by each of the passed
+// separators with the Quoted option.
+func quote(element string, separators ...string) string {
+	if element == "" ||
+		strings.ContainsAny(element, `"\`) ||
+		strings.TrimSpace(element) != element {
+		return `"` + quoteReplacer.Replace(element) + `"`
+	}
+	for _, separator := range separators {
+		if separator != "" && strings.Contains(element, separator) {
+			return `"` + quoteReplacer.Replace(element) + `"`
+		}
+	}
+	return element
+}
+
+// Join concatenates the passed elements into a single value, separated
+// by the passed separator. Any element that contains the separator,
+// a double quote, a backslash or surrounding whitespace, or that
+// is empty, is quoted so that the slice functions will return
+// the original elements when the Quoted option is used.
+func Join(elements []string, separator string) string {
+	quoted := make([]string, len(elements))
+	for i, e := range elements {
+		quoted[i] = quote(e, separator)
+	}
+	return strings.Join(quoted, separator)
+}
@@ -0,0 +1,284 @@
+package envconv_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// sliceHandler wraps a slice convertor function so that every type can be
+// exercised by the same table.
+type sliceHandler func(string, string, ...envconv.Option) (any, error)
+
+// wrapSlice converts a typed slice convertor function into a sliceHandler.
+func wrapSlice[T any](handler func(string, string, ...envconv.Option) ([]T, error)) sliceHandler {
+	return func(varName string, separator string, opts ...envconv.Option) (any, error) {
+		return handler(varName, separator, opts...)
+	}
+}
+
+func TestSliceWhitespace(t *testing.T) {
+	testData := []struct {
+		env      string
+		value    string
+		expected any
+		handler  sliceHandler
+	}{
+		{"TEST_SLICE_WHITESPACE_INT", " 1, 2 ,3 ", []int{1, 2, 3}, wrapSlice(envconv.ToIntSlice)},
+		{"TEST_SLICE_WHITESPACE_INT8", " 1, 2 ,3 ", []int8{1, 2, 3}, wrapSlice(envconv.ToInt8Slice)},
+		{"TEST_SLICE_WHITESPACE_INT16", " 1, 2 ,3 ", []int16{1, 2, 3}, wrapSlice(envconv.ToInt16Slice)},
+		{"TEST_SLICE_WHITESPACE_INT32", " 1, 2 ,3 ", []int32{1, 2, 3}, wrapSlice(envconv.ToInt32Slice)},
+		{"TEST_SLICE_WHITESPACE_INT64", " 1, 2 ,3 ", []int64{1, 2, 3}, wrapSlice(envconv.ToInt64Slice)},
+		{"TEST_SLICE_WHITESPACE_UINT", " 1, 2 ,3 ", []uint{1, 2, 3}, wrapSlice(envconv.ToUintSlice)},
+		{"TEST_SLICE_WHITESPACE_UINT8", " 1, 2 ,3 ", []uint8{1, 2, 3}, wrapSlice(envconv.ToUint8Slice)},
+		{"TEST_SLICE_WHITESPACE_UINT16", " 1, 2 ,3 ", []uint16{1, 2, 3}, wrapSlice(envconv.ToUint16Slice)},
+		{"TEST_SLICE_WHITESPACE_UINT32", " 1, 2 ,3 ", []uint32{1, 2, 3}, wrapSlice(envconv.ToUint32Slice)},
+		{"TEST_SLICE_WHITESPACE_UINT64", " 1, 2 ,3 ", []uint64{1, 2, 3}, wrapSlice(envconv.ToUint64Slice)},
+		{"TEST_SLICE_WHITESPACE_FLOAT32", " 1.5, 2 ,3 ", []float32{1.5, 2, 3}, wrapSlice(envconv.ToFloat32Slice)},
+		{"TEST_SLICE_WHITESPACE_FLOAT64", " 1.5, 2 ,3 ", []float64{1.5, 2, 3}, wrapSlice(envconv.ToFloat64Slice)},
+		{"TEST_SLICE_WHITESPACE_BOOL", " true, false ,1 ", []bool{true, false, true}, wrapSlice(envconv.ToBoolSlice)},
+		{"TEST_SLICE_WHITESPACE_DURATION", " 1s, 2m ,3h ", []time.Duration{time.Second, 2 * time.Minute, 3 * time.Hour}, wrapSlice(envconv.ToDurationSlice)},
+		{"TEST_SLICE_WHITESPACE_STRING", " a, b ,c ", []string{"a", "b", "c"}, wrapSlice(envconv.ToStringSlice)},
+	}
+
+	for _, td := range testData {
+		t.Run(td.env, func(t *testing.T) {
+			os.Setenv(td.env, td.value)
+
+			v, err := td.handler(td.env, ",")
+			assert.NoError(t, err, "there should be no error")
+			assert.Equal(t, td.expected, v, "they should be equal")
+
+			_, err = td.handler(td.env, ",", envconv.Strict())
+			if td.env == "TEST_SLICE_WHITESPACE_STRING" {
+				assert.NoError(t, err, "there should be no error")
+			} else {
+				assert.Error(t, err, "there should be an error")
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	os.Setenv("TEST_SLICE_STRICT", " a, b ,c ")
+	v, err := envconv.ToStringSlice("TEST_SLICE_STRICT", ",", envconv.Strict())
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, []string{" a", " b ", "c "}, v, "they should be equal")
+}
+
+func TestDropEmpty(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      []int
+		errorExpected bool
+		opts          []envconv.Option
+	}{
+		{"TEST_SLICE_DROP_EMPTY_MIDDLE", "1,,2", []int{1, 2}, false, nil},
+		{"TEST_SLICE_DROP_EMPTY_TRAILING", "1,2,", []int{1, 2}, false, nil},
+		{"TEST_SLICE_DROP_EMPTY_WHITESPACE", "1, ,2", []int{1, 2}, false, nil},
+		{"TEST_SLICE_DROP_EMPTY_ALL", ",,", []int{}, false, nil},
+		{"TEST_SLICE_DROP_EMPTY_STRICT", "1, ,2", []int{}, true, []envconv.Option{envconv.Strict()}},
+	}
+
+	for _, td := range testData {
+		t.Run(td.env, func(t *testing.T) {
+			os.Setenv(td.env, td.value)
+			v, err := envconv.ToIntSlice(td.env, ",", append(td.opts, envconv.DropEmpty())...)
+			if td.errorExpected {
+				assert.Error(t, err, "there should be an error")
+			} else {
+				assert.NoError(t, err, "there should be no error")
+			}
+			assert.Equal(t, td.expected, v, "they should be equal")
+		})
+	}
+
+	t.Run("without DropEmpty", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_KEEP_EMPTY", "a,,b")
+		v, err := envconv.ToStringSlice("TEST_SLICE_KEEP_EMPTY", ",")
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []string{"a", "", "b"}, v, "they should be equal")
+
+		_, err = envconv.ToIntSlice("TEST_SLICE_KEEP_EMPTY", ",")
+		assert.Error(t, err, "there should be an error")
+	})
+}
+
+func TestRejectTrailingSeparator(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      []string
+		errorExpected bool
+	}{
+		{"TEST_SLICE_TRAILING_NONE", "a,b", []string{"a", "b"}, false},
+		{"TEST_SLICE_TRAILING_COMMA", "a,b,", []string{}, true},
+		{"TEST_SLICE_TRAILING_COMMA_SPACE", "a,b, ", []string{}, true},
+	}
+
+	for _, td := range testData {
+		t.Run(td.env, func(t *testing.T) {
+			os.Setenv(td.env, td.value)
+			v, err := envconv.ToStringSlice(td.env, ",", envconv.RejectTrailingSeparator())
+			if td.errorExpected {
+				assert.ErrorIs(t, err, envconv.ErrTrailingSeparator)
+			} else {
+				assert.NoError(t, err, "there should be no error")
+			}
+			assert.Equal(t, td.expected, v, "they should be equal")
+		})
+	}
+
+	t.Run("without RejectTrailingSeparator", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_TRAILING_ALLOWED", "a,b,")
+		v, err := envconv.ToStringSlice("TEST_SLICE_TRAILING_ALLOWED", ",")
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []string{"a", "b", ""}, v, "they should be equal")
+	})
+}
+
+func TestQuoted(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		separator     string
+		expected      []string
+		errorExpected bool
+	}{
+		{"TEST_SLICE_QUOTED_PLAIN", "a,b,c", ",", []string{"a", "b", "c"}, false},
+		{"TEST_SLICE_QUOTED_HEADERS", `"a,b","c"`, ",", []string{"a,b", "c"}, false},
+		{"TEST_SLICE_QUOTED_ESCAPED_SEPARATOR", `a\,b,c`, ",", []string{"a,b", "c"}, false},
+		{"TEST_SLICE_QUOTED_REGEX", `^(a|b)\;$;x`, ";", []string{"^(a|b);$", "x"}, false},
+		{"TEST_SLICE_QUOTED_ESCAPED_QUOTE", `"say \"hi\"",b`, ",", []string{`say "hi"`, "b"}, false},
+		{"TEST_SLICE_QUOTED_ESCAPED_BACKSLASH", `a\\,b`, ",", []string{`a\`, "b"}, false},
+		{"TEST_SLICE_QUOTED_WHITESPACE", `" a ", b`, ",", []string{" a ", "b"}, false},
+		{"TEST_SLICE_QUOTED_EMPTY", `a,"",b`, ",", []string{"a", "", "b"}, false},
+		{"TEST_SLICE_QUOTED_MULTI_CHAR_SEPARATOR", `"a::b"::c`, "::", []string{"a::b", "c"}, false},
+		{"TEST_SLICE_QUOTED_UNTERMINATED", `"a,b`, ",", []string{}, true},
+	}
+
+	for _, td := range testData {
+		t.Run(td.env, func(t *testing.T) {
+			os.Setenv(td.env, td.value)
+			v, err := envconv.ToStringSlice(td.env, td.separator, envconv.Quoted())
+			if td.errorExpected {
+				assert.ErrorIs(t, err, envconv.ErrUnterminatedQuote)
+			} else {
+				assert.NoError(t, err, "there should be no error")
+			}
+			assert.Equal(t, td.expected, v, "they should be equal")
+		})
+	}
+
+	t.Run("TEST_SLICE_QUOTED_INT", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_QUOTED_INT", `"1", 2,"3"`)
+		v, err := envconv.ToIntSlice("TEST_SLICE_QUOTED_INT", ",", envconv.Quoted())
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []int{1, 2, 3}, v, "they should be equal")
+	})
+
+	t.Run("TEST_SLICE_QUOTED_DROP_EMPTY", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_QUOTED_DROP_EMPTY", `a,,"",b`)
+		v, err := envconv.ToStringSlice("TEST_SLICE_QUOTED_DROP_EMPTY", ",", envconv.Quoted(), envconv.DropEmpty())
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []string{"a", "", "b"}, v, "they should be equal")
+	})
+}
+
+func TestJoin(t *testing.T) {
+	testData := []struct {
+		name      string
+		elements  []string
+		separator string
+		expected  string
+	}{
+		{"plain", []string{"a", "b", "c"}, ",", "a,b,c"},
+		{"separator", []string{"a,b", "c"}, ",", `"a,b",c`},
+		{"quote", []string{`say "hi"`}, ",", `"say \"hi\""`},
+		{"backslash", []string{`a\b`}, ",", `"a\\b"`},
+		{"whitespace", []string{" a ", "b"}, ",", `" a ",b`},
+		{"empty", []string{"a", "", "b"}, ",", `a,"",b`},
+		{"multi char separator", []string{"a::b", "c"}, "::", `"a::b"::c`},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			joined := envconv.Join(td.elements, td.separator)
+			assert.Equal(t, td.expected, joined, "they should be equal")
+
+			os.Setenv("TEST_JOIN_ROUND_TRIP", joined)
+			v, err := envconv.ToStringSlice("TEST_JOIN_ROUND_TRIP", td.separator, envconv.Quoted())
+			assert.NoError(t, err, "there should be no error")
+			assert.Equal(t, td.elements, v, "they should be equal")
+		})
+	}
+}
+
+func TestElementError(t *testing.T) {
+	os.Setenv("TEST_SLICE_ELEMENT_ERROR", "80,eighty,443")
+
+	v, err := envconv.ToIntSlice("TEST_SLICE_ELEMENT_ERROR", ",")
+	assert.Equal(t, []int{}, v, "they should be equal")
+
+	var elementErr *envconv.ElementError
+	if assert.ErrorAs(t, err, &elementErr) {
+		assert.Equal(t, "TEST_SLICE_ELEMENT_ERROR", elementErr.VarName, "they should be equal")
+		assert.Equal(t, 1, elementErr.Index, "they should be equal")
+		assert.Equal(t, "eighty", elementErr.Element, "they should be equal")
+		assert.Contains(t, err.Error(), `TEST_SLICE_ELEMENT_ERROR: element 1 "eighty"`)
+	}
+
+	t.Run("nested", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_2D_ELEMENT_ERROR", "1,2;3,x")
+		_, err := envconv.ToIntSlice2D("TEST_SLICE_2D_ELEMENT_ERROR", ";", ",")
+		assert.EqualError(t, err, `TEST_SLICE_2D_ELEMENT_ERROR: element [1][1] "x": strconv.ParseInt: parsing "x": invalid syntax`)
+	})
+}
+
+func TestLenient(t *testing.T) {
+	os.Setenv("TEST_SLICE_LENIENT", "80,eighty,443,x")
+
+	v, err := envconv.ToIntSlice("TEST_SLICE_LENIENT", ",", envconv.Lenient())
+	assert.Equal(t, []int{80, 443}, v, "they should be equal")
+
+	var elementErrors envconv.ElementErrors
+	if assert.ErrorAs(t, err, &elementErrors) {
+		assert.Len(t, elementErrors, 2)
+
+		var elementErr *envconv.ElementError
+		assert.ErrorAs(t, elementErrors[1], &elementErr)
+		assert.Equal(t, 3, elementErr.Index, "they should be equal")
+		assert.Equal(t, "x", elementErr.Element, "they should be equal")
+	}
+
+	t.Run("with default", func(t *testing.T) {
+		v := envconv.ToIntSliceWithDefault("TEST_SLICE_LENIENT", ",", []int{1}, envconv.Lenient())
+		assert.Equal(t, []int{80, 443}, v, "they should be equal")
+
+		v = envconv.ToIntSliceWithDefault("TEST_SLICE_LENIENT", ",", []int{1})
+		assert.Equal(t, []int{1}, v, "they should be equal")
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_2D_LENIENT", "1,x;y,4")
+		v, err := envconv.ToIntSlice2D("TEST_SLICE_2D_LENIENT", ";", ",", envconv.Lenient())
+		assert.Equal(t, [][]int{{1}, {4}}, v, "they should be equal")
+
+		var nestedErr *envconv.NestedElementError
+		if assert.ErrorAs(t, err, &nestedErr) {
+			assert.Equal(t, 0, nestedErr.Outer, "they should be equal")
+			assert.Equal(t, 1, nestedErr.Inner, "they should be equal")
+		}
+	})
+
+	t.Run("all valid", func(t *testing.T) {
+		os.Setenv("TEST_SLICE_LENIENT_VALID", "1,2")
+		v, err := envconv.ToIntSlice("TEST_SLICE_LENIENT_VALID", ",", envconv.Lenient())
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []int{1, 2}, v, "they should be equal")
+	})
+}
@@ -0,0 +1,97 @@
+package envconv
+
+import (
+	"sort"
+	"strings"
+)
+
+// Env is an immutable set of environment variables, held in memory.
+// It implements Source, so it can be passed to any function with
+// the FromSource option, including Decode and NewLoader, and
+// methods that narrow it down return a new Env rather
+// than changing it.
+type Env struct {
+	values map[string]string
+}
+
+// Snapshot returns an Env holding a copy of the current process
+// environment. Later changes to the environment, such as calls
+// to os.Setenv, do not affect it, and looking variables up
+// in it does not go back to the operating system.
+func Snapshot() *Env {
+	return &Env{values: environMap(Environment)}
+}
+
+// NewEnv returns an Env holding a copy of the passed variables.
+func NewEnv(values map[string]string) *Env {
+	e := &Env{values: make(map[string]string, len(values))}
+	for name, value := range values {
+		e.values[name] = value
+	}
+	return e
+}
+
+// Lookup returns the value of the named variable, and whether
+// it is set.
+func (e *Env) Lookup(name string) (string, bool) {
+	value, ok := e.values[name]
+	return value, ok
+}
+
+// Environ returns every variable in the form "key=value", in
+// lexicographical order of name. The result can be passed
+// to exec.Cmd as its Env.
+func (e *Env) Environ() []string {
+	return environ(e.values)
+}
+
+// Len returns the number of variables.
+func (e *Env) Len() int {
+	return len(e.values)
+}
+
+// Names returns the name of every variable, in lexicographical order.
+func (e *Env) Names() []string {
+	names := make([]string, 0, len(e.values))
+	for name := range e.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Map returns a copy of the variables as a map.
+func (e *Env) Map() map[string]string {
+	values := make(map[string]string, len(e.values))
+	for name, value := range e.values {
+		values[name] = value
+	}
+	return values
+}
+
+// Filter returns a new Env holding only the variables for which
+// keep returns true.
+func (e *Env) Filter(keep func(name string, value string) bool) *Env {
+	filtered := &Env{values: map[string]string{}}
+	for name, value := range e.values {
+		if keep(name, value) {
+			filtered.values[name] = value
+		}
+	}
+	return filtered
+}
+
+// FilterPrefix returns a new Env holding only the variables whose
+// names start with prefix.
+func (e *Env) FilterPrefix(prefix string) *Env {
+	return e.Filter(func(name string, _ string) bool {
+		return strings.HasPrefix(name, prefix)
+	})
+}
+
+// Diff returns the variables that differ between e and to, in
+// lexicographical order of name. A variable only set in to
+// is reported as Added, and one only set in e as Removed.
+func (e *Env) Diff(to Source) []Change {
+	return diff(e, to)
+}
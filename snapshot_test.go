@@ -0,0 +1,102 @@
+package envconv_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+	os.Setenv("TEST_SNAPSHOT_PORT", "9090")
+	os.Setenv("TEST_SNAPSHOT_HOST", "localhost")
+	os.Unsetenv("TEST_SNAPSHOT_DEBUG")
+
+	snap := envconv.Snapshot()
+	os.Setenv("TEST_SNAPSHOT_PORT", "8080")
+	os.Setenv("TEST_SNAPSHOT_DEBUG", "true")
+	os.Unsetenv("TEST_SNAPSHOT_HOST")
+
+	port, err := envconv.ToInt("TEST_SNAPSHOT_PORT", envconv.FromSource(snap))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 9090, port, "they should be equal")
+
+	_, err = envconv.ToBool("TEST_SNAPSHOT_DEBUG", envconv.FromSource(snap))
+	assert.ErrorIs(t, err, envconv.ErrNotSet)
+
+	t.Run("Diff", func(t *testing.T) {
+		changes := snap.FilterPrefix("TEST_SNAPSHOT_").Diff(envconv.Snapshot().FilterPrefix("TEST_SNAPSHOT_"))
+		assert.Equal(t, []envconv.Change{
+			{Name: "TEST_SNAPSHOT_DEBUG", Kind: envconv.Added, New: "true"},
+			{Name: "TEST_SNAPSHOT_HOST", Kind: envconv.Removed, Old: "localhost"},
+			{Name: "TEST_SNAPSHOT_PORT", Kind: envconv.Modified, Old: "9090", New: "8080"},
+		}, changes, "they should be equal")
+
+		assert.Nil(t, snap.Diff(snap), "there should be no changes")
+	})
+
+	t.Run("Loader", func(t *testing.T) {
+		var cfg struct {
+			Port  int    `env:"PORT"`
+			Host  string `env:"HOST" default:"0.0.0.0"`
+			Debug bool   `env:"DEBUG"`
+		}
+		l := envconv.NewLoader(envconv.FromSource(snap), envconv.Prefix("TEST_SNAPSHOT_"))
+		assert.NoError(t, l.Decode(&cfg), "there should be no error")
+		assert.Equal(t, 9090, cfg.Port, "they should be equal")
+		assert.Equal(t, "localhost", cfg.Host, "they should be equal")
+		assert.False(t, cfg.Debug, "later changes to the environment should not be seen")
+
+		assert.NoError(t, l.Decode(&cfg, envconv.FromSource(snap.FilterPrefix("TEST_SNAPSHOT_P"))), "there should be no error")
+		assert.Equal(t, "0.0.0.0", cfg.Host, "a filtered snapshot should be decoded")
+	})
+}
+
+func TestEnv(t *testing.T) {
+	values := map[string]string{
+		"APP_PORT":  "9090",
+		"APP_HOSTS": "a,b",
+		"HOME":      "/root",
+		"EMPTY":     "",
+	}
+	env := envconv.NewEnv(values)
+	values["APP_PORT"] = "1"
+
+	assert.Equal(t, 4, env.Len(), "they should be equal")
+	assert.Equal(t, []string{"APP_HOSTS", "APP_PORT", "EMPTY", "HOME"}, env.Names(), "they should be equal")
+	assert.Equal(t, []string{"APP_HOSTS=a,b", "APP_PORT=9090", "EMPTY=", "HOME=/root"}, env.Environ(), "they should be equal")
+
+	m := env.Map()
+	m["APP_PORT"] = "2"
+	v, _ := env.Lookup("APP_PORT")
+	assert.Equal(t, "9090", v, "they should be equal")
+
+	t.Run("FilterPrefix", func(t *testing.T) {
+		assert.Equal(t, []string{"APP_HOSTS=a,b", "APP_PORT=9090"}, env.FilterPrefix("APP_").Environ(), "they should be equal")
+		assert.Equal(t, 4, env.Len(), "it should not change")
+	})
+
+	t.Run("Filter", func(t *testing.T) {
+		nonEmpty := env.Filter(func(name, value string) bool {
+			return value != "" && !strings.HasPrefix(name, "APP_")
+		})
+		assert.Equal(t, []string{"HOME=/root"}, nonEmpty.Environ(), "they should be equal")
+	})
+
+	t.Run("order of name", func(t *testing.T) {
+		env := envconv.NewEnv(map[string]string{"A-B": "1", "A": "2"})
+		assert.Equal(t, []string{"A", "A-B"}, env.Names(), "they should be equal")
+		assert.Equal(t, []string{"A=2", "A-B=1"}, env.Environ(), "they should be sorted by name")
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		parsed := map[string]string{}
+		for _, kv := range env.Environ() {
+			name, value, _ := strings.Cut(kv, "=")
+			parsed[name] = value
+		}
+		assert.Equal(t, env.Map(), parsed, "they should be equal")
+	})
+}
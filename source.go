@@ -0,0 +1,265 @@
+package envconv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Source is a set of environment variables that values can be loaded
+// from, in place of the process environment. Pass a Source to any
+// function with the FromSource option.
+type Source interface {
+	// Lookup returns the value of the named variable, and whether
+	// it is set.
+	Lookup(name string) (string, bool)
+
+	// Environ returns every variable in the form "key=value", in the
+	// same way as os.Environ.
+	Environ() []string
+}
+
+// Setter is implemented by a Source whose variables can be changed,
+// such as Environment or a MutableEnv. Pass a Setter to the Set
+// functions with the Into option.
+type Setter interface {
+	// Set sets the value of the named variable.
+	Set(name string, value string) error
+}
+
+// Environment is the Source for the process environment, which is
+// used when no other Source is passed. It also implements Setter,
+// using os.Setenv.
+var Environment Source = environment{}
+
+// environment implements Source and Setter using the os package.
+type environment struct{}
+
+func (environment) Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+func (environment) Environ() []string {
+	return os.Environ()
+}
+
+func (environment) Set(name string, value string) error {
+	return os.Setenv(name, value)
+}
+
+// MutableEnv is a Source held in memory whose variables can be
+// changed with Set and Unset. It is safe for concurrent use.
+type MutableEnv struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewMutableEnv returns a MutableEnv holding a copy of the passed
+// variables, which may be nil.
+func NewMutableEnv(values map[string]string) *MutableEnv {
+	return &MutableEnv{values: NewEnv(values).values}
+}
+
+// Lookup returns the value of the named variable, and whether
+// it is set.
+func (e *MutableEnv) Lookup(name string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	value, ok := e.values[name]
+	return value, ok
+}
+
+// Environ returns every variable in the form "key=value", in
+// lexicographical order of name.
+func (e *MutableEnv) Environ() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return environ(e.values)
+}
+
+// Set sets the value of the named variable. As with os.Setenv, an
+// error is returned if the name is empty or contains "=" or NUL.
+func (e *MutableEnv) Set(name string, value string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.values[name] = value
+	return nil
+}
+
+// checkName returns an error if the passed name cannot be used for
+// an environment variable, because it is empty or contains "="
+// or NUL.
+func checkName(name string) error {
+	if name == "" || strings.ContainsAny(name, "=\x00") {
+		return fmt.Errorf("envconv: invalid variable name %q", name)
+	}
+	return nil
+}
+
+// Unset removes the named variable.
+func (e *MutableEnv) Unset(name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.values, name)
+}
+
+// Snapshot returns an immutable copy of the current variables.
+func (e *MutableEnv) Snapshot() *Env {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return NewEnv(e.values)
+}
+
+// locator is implemented by a Source that can describe where each
+// of its variables was defined, such as a file name and line.
+type locator interface {
+	Locate(name string) string
+}
+
+// FileSource is a Source read from a dotenv file, or from a directory
+// holding one file per variable. It does not change once read.
+type FileSource struct {
+	path      string
+	values    map[string]string
+	locations map[string]string
+}
+
+// ReadFileSource reads the dotenv file or directory at path. A dotenv
+// file is parsed with ParseDotenv, and a later assignment to the same
+// name replaces an earlier one. In a directory, such as a mounted
+// Kubernetes ConfigMap or Secret, each regular file whose name does
+// not start with a dot becomes a variable named after the file. The
+// file contents, less one trailing newline, become its value.
+func ReadFileSource(path string) (*FileSource, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileSource{path: path, values: map[string]string{}, locations: map[string]string{}}
+	if info.IsDir() {
+		err = s.readDir()
+	} else {
+		err = s.readDotenv()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// readDotenv reads the variables of the dotenv file at s.path.
+func (s *FileSource) readDotenv() error {
+	f, err := os.Open(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	entries, err := ParseDotenv(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", s.path, err)
+	}
+	for _, e := range entries {
+		s.values[e.Name] = e.Value
+		s.locations[e.Name] = fmt.Sprintf("%s:%d", s.path, e.Line)
+	}
+	return nil
+}
+
+// readDir reads one variable from each file in the directory at s.path.
+func (s *FileSource) readDir() error {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(s.path, e.Name())
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		value := strings.TrimSuffix(string(b), "\n")
+		s.values[e.Name()] = strings.TrimSuffix(value, "\r")
+		s.locations[e.Name()] = path
+	}
+	return nil
+}
+
+// Path returns the path the FileSource was read from.
+func (s *FileSource) Path() string {
+	return s.path
+}
+
+// Lookup returns the value of the named variable, and whether
+// it is set.
+func (s *FileSource) Lookup(name string) (string, bool) {
+	value, ok := s.values[name]
+	return value, ok
+}
+
+// Environ returns every variable in the form "key=value", in
+// lexicographical order of name.
+func (s *FileSource) Environ() []string {
+	return environ(s.values)
+}
+
+// Locate returns where the named variable was defined, as a file
+// name and line for a dotenv file or a file name for a directory.
+// It returns an empty string if the variable is not set.
+func (s *FileSource) Locate(name string) string {
+	return s.locations[name]
+}
+
+// environ returns the passed variables in the form "key=value",
+// in lexicographical order of name.
+func environ(values map[string]string) []string {
+	names := sortedNames(values)
+	env := make([]string, len(names))
+	for i, name := range names {
+		env[i] = name + "=" + values[name]
+	}
+	return env
+}
+
+// sortedNames returns the names of the passed variables, in
+// lexicographical order.
+func sortedNames(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// environMap returns the variables of the passed Source as a map.
+// Entries without a name, such as the "=C:" entries Windows
+// adds to its environment, are skipped. When a name is
+// repeated the first entry wins, as it does for
+// os.LookupEnv.
+func environMap(src Source) map[string]string {
+	values := map[string]string{}
+	for _, kv := range src.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if _, seen := values[name]; name != "" && !seen {
+			values[name] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,87 @@
+package envconv_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeFile writes the passed contents to the file at path,
+// returning the path.
+func writeFile(t *testing.T, path string, contents string) string {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadFileSource(t *testing.T) {
+	t.Run("dotenv file", func(t *testing.T) {
+		path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "# settings\nPORT=9090\nHOSTS=\"a, b\"\nPORT=9091\n")
+		src, err := envconv.ReadFileSource(path)
+		assert.NoError(t, err, "there should be no error")
+
+		v, ok := src.Lookup("PORT")
+		assert.True(t, ok, "it should be set")
+		assert.Equal(t, "9091", v, "they should be equal")
+		assert.Equal(t, path+":4", src.Locate("PORT"), "they should be equal")
+		assert.Equal(t, []string{"HOSTS=a, b", "PORT=9091"}, src.Environ(), "they should be equal")
+
+		_, ok = src.Lookup("MISSING")
+		assert.False(t, ok, "it should not be set")
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "PASSWORD"), "hunter2\n")
+		writeFile(t, filepath.Join(dir, "TOKEN"), "abc")
+		writeFile(t, filepath.Join(dir, ".hidden"), "x")
+		assert.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o700))
+
+		src, err := envconv.ReadFileSource(dir)
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, []string{"PASSWORD=hunter2", "TOKEN=abc"}, src.Environ(), "they should be equal")
+		assert.Equal(t, filepath.Join(dir, "TOKEN"), src.Locate("TOKEN"), "they should be equal")
+	})
+
+	t.Run("invalid dotenv file", func(t *testing.T) {
+		path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "PORT=9090\nnot an assignment\n")
+		_, err := envconv.ReadFileSource(path)
+		assert.ErrorContains(t, err, "dotenv line 2: invalid assignment")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := envconv.ReadFileSource(filepath.Join(t.TempDir(), "missing"))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestFromSource(t *testing.T) {
+	os.Setenv("TEST_SOURCE_PORT", "8080")
+	os.Unsetenv("TEST_SOURCE_HOSTS")
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "TEST_SOURCE_PORT=9090\nTEST_SOURCE_OLD_HOSTS=a,b\n")
+	src, err := envconv.ReadFileSource(path)
+	assert.NoError(t, err, "there should be no error")
+
+	r := envconv.NewReport()
+	port, err := envconv.ToInt("TEST_SOURCE_PORT", envconv.FromSource(src), envconv.Record(r))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 9090, port, "they should be equal")
+
+	hosts, err := envconv.ToStringSlice("TEST_SOURCE_HOSTS", ",", envconv.FromSource(src), envconv.Aliases("TEST_SOURCE_OLD_HOSTS"), envconv.Record(r))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, []string{"a", "b"}, hosts, "they should be equal")
+
+	assert.Equal(t, []envconv.Provenance{
+		{Name: "TEST_SOURCE_HOSTS", Origin: envconv.OriginSource, From: "TEST_SOURCE_OLD_HOSTS", Location: path + ":2", Value: "a,b"},
+		{Name: "TEST_SOURCE_PORT", Origin: envconv.OriginSource, Location: path + ":1", Value: "9090"},
+	}, r.Entries(), "they should be equal")
+
+	port, err = envconv.ToInt("TEST_SOURCE_PORT", envconv.FromSource(envconv.Environment))
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 8080, port, "they should be equal")
+}
@@ -1,16 +1,17 @@
 package envconv
 
-import "strings"
+// convertString returns the passed string unchanged. It exists so
+// that strings can share the same conversion path as every
+// other type.
+func convertString(value string) (string, error) {
+	return value, nil
+}
 
 // ToString returns the value of the requested environment variable
 // without converting from the original string. An error will be
 // returned if the environment variable is not found.
-//
-// This function is a convenience wrapper around
-// loadFromEnvironment, for the sake of pattern
-// consistency.
-func ToString(varName string) (string, error) {
-	return loadFromEnvironment(varName, true)
+func ToString(varName string, opts ...Option) (string, error) {
+	return convert(varName, opts, convertString)
 }
 
 // ToStringSlice returns the value of the requested environment variable
@@ -18,37 +19,43 @@ func ToString(varName string) (string, error) {
 // separator. An error will be returned if the
 // environment variable is not found or the conversion to a
 // slice of strings fails.
-func ToStringSlice(varName string, separator string) ([]string, error) {
-	value, err := loadFromEnvironment(varName, true)
-	if err != nil {
-		return []string{}, err
-	}
-
-	return strings.Split(value, separator), nil
+func ToStringSlice(varName string, separator string, opts ...Option) ([]string, error) {
+	return convertSlice(varName, separator, opts, convertString)
 }
 
 // ToStringWithDefault returns the value of the requested environment
 // variable without converting from the original string. The default
 // value passed as the second parameter will be returned if the
 // environment variable is not found.
-//
-// This function is a convenience wrapper around
-// loadFromEnvironmentWithDefault, for the sake
-// of pattern consistency.
-func ToStringWithDefault(varName string, defaultValue string) string {
-	return loadFromEnvironmentWithDefault(varName, defaultValue)
+func ToStringWithDefault(varName string, defaultValue string, opts ...Option) string {
+	value, err := ToString(varName, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
 
-// ToStringSliceWithDefault returns the value of the requested environment 
+// ToStringSliceWithDefault returns the value of the requested environment
 // variable as a slice of strings that have been split by the passed
-// separator. The passed default slice will be returned if the 
-// environment variable is not found or the conversion to a 
+// separator. The passed default slice will be returned if the
+// environment variable is not found or the conversion to a
 // slice of strings fails.
-func ToStringSliceWithDefault(varName string, separator string, defaultValue []string) []string {
-	value, err := ToStringSlice(varName, separator)
-	if err != nil {
-		return defaultValue
-	}
+func ToStringSliceWithDefault(varName string, separator string, defaultValue []string, opts ...Option) []string {
+	value, err := ToStringSlice(varName, separator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
+}
+
+// ToStringSlice2D returns the value of the requested environment variable
+// converted to a nested slice of strings. The value is split by the
+// outer separator, then each part is split by the inner separator.
+// An error will be returned if the environment variable is not
+// found or the conversion of any element fails.
+func ToStringSlice2D(varName string, outerSeparator string, innerSeparator string, opts ...Option) ([][]string, error) {
+	return convertSlice2D(varName, outerSeparator, innerSeparator, opts, convertString)
+}
 
-	return value
+// ToStringSlice2DWithDefault returns the value of the requested environment
+// variable converted to a nested slice of strings. The default value
+// passed as the fourth parameter will be returned if the environment
+// variable is not found or the conversion of any element fails.
+func ToStringSlice2DWithDefault(varName string, outerSeparator string, innerSeparator string, defaultValue [][]string, opts ...Option) [][]string {
+	value, err := ToStringSlice2D(varName, outerSeparator, innerSeparator, opts...)
+	return withDefault(varName, value, err, defaultValue, opts)
 }
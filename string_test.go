@@ -131,3 +131,26 @@ func TestToStringSliceWithDefault(t *testing.T) {
 		assert.Equal(t, []string{}, v, "they should be equal")
 	})
 }
+
+func TestToStringSlice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]string
+		errorExpected bool
+	}{
+		{"TEST_STRING_SLICE_2D_HOSTS", "a,b;c", [][]string{{"a", "b"}, {"c"}}, false},
+		{"TEST_STRING_SLICE_2D_EMPTY_ROW", "a;;b", [][]string{{"a"}, {""}, {"b"}}, false},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToStringSlice2D)
+	}
+
+	t.Run("TEST_STRING_SLICE_2D_QUOTED", func(t *testing.T) {
+		os.Setenv("TEST_STRING_SLICE_2D_QUOTED", `"a;b",c;d`)
+		v, err := envconv.ToStringSlice2D("TEST_STRING_SLICE_2D_QUOTED", ";", ",", envconv.Quoted())
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, [][]string{{"a;b", "c"}, {"d"}}, v, "they should be equal")
+	})
+}
@@ -0,0 +1,237 @@
+package envconv
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUndeclared is reported by VarSet.CheckUnknown for a variable
+// that is set, but has not been declared.
+var ErrUndeclared = errors.New("undeclared environment variable")
+
+// maxSuggestions is the largest number of similar names listed in
+// a single error.
+const maxSuggestions = 3
+
+// NotSetError is returned when the requested environment variable is
+// not set. It wraps ErrNotSet, and lists any variables that are
+// set with similar names and the same prefix, which are likely
+// to be misspellings.
+type NotSetError struct {
+	VarName string
+
+	src         Source
+	prefix      string
+	empty       EmptyPolicy
+	once        sync.Once
+	suggestions []string
+}
+
+// Suggestions returns the names of variables that are set with names
+// similar to VarName, closest first. As the error is often handled
+// without being shown, such as by the WithDefault functions, the
+// Source is only searched the first time Suggestions or Error
+// is called.
+func (e *NotSetError) Suggestions() []string {
+	e.once.Do(func() {
+		if e.src == nil {
+			return
+		}
+		var candidates []string
+		for name, value := range environMap(e.src) {
+			if value == "" && e.empty == EmptyAsUnset {
+				continue
+			}
+			if len(name) >= len(e.prefix) && strings.EqualFold(name[:len(e.prefix)], e.prefix) {
+				candidates = append(candidates, name)
+			}
+		}
+		e.suggestions = suggest(e.VarName, candidates)
+	})
+	return e.suggestions
+}
+
+// Error implements the error interface.
+func (e *NotSetError) Error() string {
+	return e.message() + didYouMean(e.Suggestions())
+}
+
+// message returns the error message without any suggestions, which
+// is what a Report records, so that recording an unset variable
+// does not search the Source.
+func (e *NotSetError) message() string {
+	return fmt.Sprintf("%s: %v", e.VarName, ErrNotSet)
+}
+
+// Unwrap returns ErrNotSet.
+func (e *NotSetError) Unwrap() error {
+	return ErrNotSet
+}
+
+// UndeclaredError is reported by VarSet.CheckUnknown for a variable
+// that is set but not declared. It wraps ErrUndeclared, and lists
+// any declared variables with similar names, which the variable
+// is likely to be a misspelling of.
+type UndeclaredError struct {
+	VarName string
+
+	declared    []string
+	once        sync.Once
+	suggestions []string
+}
+
+// Suggestions returns the names of declared variables similar to
+// VarName, closest first. Like NotSetError.Suggestions, they are
+// only found the first time Suggestions or Error is called.
+func (e *UndeclaredError) Suggestions() []string {
+	e.once.Do(func() {
+		e.suggestions = suggest(e.VarName, e.declared)
+	})
+	return e.suggestions
+}
+
+// Error implements the error interface.
+func (e *UndeclaredError) Error() string {
+	return fmt.Sprintf("%s: %v%s", e.VarName, ErrUndeclared, didYouMean(e.Suggestions()))
+}
+
+// Unwrap returns ErrUndeclared.
+func (e *UndeclaredError) Unwrap() error {
+	return ErrUndeclared
+}
+
+// didYouMean returns the passed suggestions as a parenthesised
+// clause to append to an error message, or an empty string
+// if there are none.
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return " (did you mean " + strings.Join(suggestions, " or ") + "?)"
+}
+
+// notSet returns the error for the named variable not being set,
+// which suggests the names of similar variables that are set in the
+// Source in the passed options. Only names with the prefix of the
+// SuggestPrefix option, or else the prefix of varName up to its
+// first underscore, are suggested.
+func notSet(varName string, o *options) error {
+	src := o.source
+	if src == nil {
+		src = Environment
+	}
+	prefix := o.suggestPrefix
+	if prefix == "" {
+		if i := strings.Index(varName, "_"); i >= 0 {
+			prefix = varName[:i+1]
+		}
+	}
+	return &NotSetError{VarName: varName, src: src, prefix: prefix, empty: o.empty}
+}
+
+// suggest returns the candidates that are likely to be misspellings
+// of name, closest first. A candidate matches if it is equal to name
+// when case is ignored, or if it is within a small edit distance,
+// which grows with the length of name.
+func suggest(name string, candidates []string) []string {
+	limit := len(name) / 5
+	if limit < 1 {
+		limit = 1
+	}
+
+	type match struct {
+		name     string
+		distance int
+	}
+	var matches []match
+	for _, c := range candidates {
+		if c == name {
+			continue
+		}
+		if d := distance(strings.ToUpper(name), strings.ToUpper(c)); d <= limit {
+			matches = append(matches, match{c, d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].distance != matches[j].distance {
+			return matches[i].distance < matches[j].distance
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	var suggestions []string
+	for i := 0; i < len(matches) && i < maxSuggestions; i++ {
+		suggestions = append(suggestions, matches[i].name)
+	}
+	return suggestions
+}
+
+// distance returns the number of single character insertions,
+// deletions, substitutions and transpositions of adjacent
+// characters needed to turn a into b.
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+// CheckUnknown checks src, or the process environment if src is nil,
+// for variables that start with prefix but have not been declared
+// in the VarSet, either by name or as an alias. These are likely
+// to be misspellings of declared variables, which would silently
+// fall back to their defaults. CheckUnknown returns an error
+// joining an UndeclaredError for each such variable, in
+// lexicographical order of name, or nil if there
+// are none.
+func (s *VarSet) CheckUnknown(src Source, prefix string) error {
+	if src == nil {
+		src = Environment
+	}
+	known := map[string]bool{}
+	var declared []string
+	s.VisitAll(func(v *Var) {
+		declared = append(declared, v.Name)
+		for _, name := range append([]string{v.Name}, v.opts.aliases...) {
+			known[name] = true
+			if v.opts.fileSecrets {
+				known[name+fileSuffix] = true
+			}
+		}
+	})
+
+	var unknown []string
+	for name := range environMap(src) {
+		if strings.HasPrefix(name, prefix) && !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+
+	var errs []error
+	for _, name := range unknown {
+		errs = append(errs, &UndeclaredError{VarName: name, declared: declared})
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,138 @@
+package envconv_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotSetSuggestions(t *testing.T) {
+	src := envconv.FromSource(envconv.NewEnv(map[string]string{
+		"DATABSE_URL":  "postgres://localhost",
+		"DATABASE_ULR": "postgres://localhost",
+		"database_url": "postgres://localhost",
+		"B_PORT":       "80",
+		"APP_PROT":     "9090",
+		"APP_PORTS":    "9090,9091",
+		"OTHER_PORT":   "80",
+		"APP_EMPTY":    "",
+		"HOME":         "/home/app",
+	}))
+
+	testData := []struct {
+		name     string
+		varName  string
+		opts     []envconv.Option
+		expected []string
+	}{
+		{"misspelling", "DATABASE_URL", nil, []string{"database_url", "DATABASE_ULR"}},
+		{"misspelling, empty prefix", "DATABASE_URL", []envconv.Option{envconv.SuggestPrefix("")}, []string{"database_url", "DATABASE_ULR"}},
+		{"misspelling, other prefix", "DATABASE_URL", []envconv.Option{envconv.SuggestPrefix("DATA")}, []string{"database_url", "DATABASE_ULR", "DATABSE_URL"}},
+		{"other prefix by default", "A_PORT", nil, nil},
+		{"transposition", "APP_PORT", nil, []string{"APP_PORTS", "APP_PROT"}},
+		{"prefix", "APP_PORT", []envconv.Option{envconv.SuggestPrefix("APP_")}, []string{"APP_PORTS", "APP_PROT"}},
+		{"other prefix", "APP_PORT", []envconv.Option{envconv.SuggestPrefix("OTHER_")}, nil},
+		{"empty as unset", "APP_EMPTYY", []envconv.Option{envconv.WithEmpty(envconv.EmptyAsUnset)}, nil},
+		{"empty as set", "APP_EMPTYY", nil, []string{"APP_EMPTY"}},
+		{"nothing similar", "LOG_LEVEL", nil, nil},
+	}
+
+	for _, td := range testData {
+		t.Run(td.name, func(t *testing.T) {
+			_, err := envconv.ToString(td.varName, append(td.opts, src)...)
+			assert.ErrorIs(t, err, envconv.ErrNotSet, "the error should wrap ErrNotSet")
+			var notSet *envconv.NotSetError
+			if assert.ErrorAs(t, err, &notSet, "the error should be a NotSetError") {
+				assert.Equal(t, td.varName, notSet.VarName, "they should be equal")
+				assert.Equal(t, td.expected, notSet.Suggestions(), "they should be equal")
+			}
+		})
+	}
+
+	_, err := envconv.ToString("DATABASE_URL", src)
+	assert.EqualError(t, err, "DATABASE_URL: unknown environment variable (did you mean database_url or DATABASE_ULR?)")
+	_, err = envconv.ToString("LOG_LEVEL", src)
+	assert.EqualError(t, err, "LOG_LEVEL: unknown environment variable")
+}
+
+// countingSource is a Source that counts the calls to Environ.
+type countingSource struct {
+	envconv.Source
+	environ int
+}
+
+func (s *countingSource) Environ() []string {
+	s.environ++
+	return s.Source.Environ()
+}
+
+func TestNotSetSuggestionsLazy(t *testing.T) {
+	src := &countingSource{Source: envconv.NewEnv(map[string]string{"APP_PROT": "9090"})}
+
+	value := envconv.ToIntWithDefault("APP_PORT", 8080, envconv.FromSource(src))
+	assert.Equal(t, 8080, value, "they should be equal")
+	assert.Equal(t, 0, src.environ, "the Source should not be searched for a default")
+
+	_, err := envconv.ToInt("APP_PORT", envconv.FromSource(src))
+	assert.Equal(t, 0, src.environ, "the Source should not be searched until asked")
+	assert.EqualError(t, err, "APP_PORT: unknown environment variable (did you mean APP_PROT?)")
+	assert.EqualError(t, err, "APP_PORT: unknown environment variable (did you mean APP_PROT?)")
+	assert.Equal(t, 1, src.environ, "the Source should be searched once")
+
+	r := envconv.NewReport()
+	envconv.ToIntWithDefault("APP_PORT", 8080, envconv.FromSource(src), envconv.Record(r))
+	s := envconv.NewVarSet("test")
+	s.Int("APP_PORT", 8080, "", envconv.FromSource(src), envconv.Record(r))
+	s.Int("APP_WORKERS", 4, "", envconv.Required(), envconv.FromSource(src), envconv.Record(r))
+	assert.Error(t, s.Parse(), "there should be an error")
+	assert.Equal(t, 1, src.environ, "the Source should not be searched to record the error")
+	p, _ := r.Lookup("APP_PORT")
+	assert.Equal(t, "APP_PORT: unknown environment variable", p.Error, "they should be equal")
+	p, _ = r.Lookup("APP_WORKERS")
+	assert.Equal(t, "APP_WORKERS: unknown environment variable", p.Error, "they should be equal")
+}
+
+func BenchmarkToIntWithDefaultUnset(b *testing.B) {
+	opt := envconv.FromSource(envconv.Snapshot())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if envconv.ToIntWithDefault("BENCHMARK_ENVCONV_UNSET", 8080, opt) != 8080 {
+			b.Fatal("the default should be returned")
+		}
+	}
+}
+
+func TestCheckUnknown(t *testing.T) {
+	src := envconv.NewEnv(map[string]string{
+		"APP_PORT":     "9090",
+		"APP_OLD_HOST": "localhost",
+		"APP_TIMOEUT":  "30s",
+		"APP_DEBG":     "true",
+		"APP_LEGACY":   "1",
+		"HOME":         "/home/app",
+	})
+
+	s := envconv.NewVarSet("test")
+	s.Int("APP_PORT", 8080, "port to listen on")
+	s.String("APP_HOST", "", "host to bind", envconv.Aliases("APP_OLD_HOST"))
+	s.Duration("APP_TIMEOUT", 0, "request timeout")
+	s.Bool("APP_DEBUG", false, "enable debug logging")
+
+	err := s.CheckUnknown(src, "APP_")
+	assert.ErrorIs(t, err, envconv.ErrUndeclared, "the error should wrap ErrUndeclared")
+	assert.EqualError(t, err, "APP_DEBG: undeclared environment variable (did you mean APP_DEBUG?)\n"+
+		"APP_LEGACY: undeclared environment variable\n"+
+		"APP_TIMOEUT: undeclared environment variable (did you mean APP_TIMEOUT?)")
+
+	var undeclared *envconv.UndeclaredError
+	if assert.ErrorAs(t, err, &undeclared, "the error should hold an UndeclaredError") {
+		assert.Equal(t, "APP_DEBG", undeclared.VarName, "they should be equal")
+		assert.Equal(t, []string{"APP_DEBUG"}, undeclared.Suggestions(), "they should be equal")
+	}
+
+	assert.NoError(t, s.CheckUnknown(src, "LOG_"), "there should be no error")
+	assert.Error(t, s.CheckUnknown(src, ""), "HOME should be reported without a prefix")
+	assert.False(t, errors.Is(s.CheckUnknown(src, "APP_PORT"), envconv.ErrUndeclared), "declared names should not be reported")
+}
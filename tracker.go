@@ -0,0 +1,103 @@
+package envconv
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Tracker wraps a Source and records the name of every variable
+// looked up through it, so that variables that are set but never
+// read, or read but never set, can be found. Pass a Tracker to
+// the functions that load variables with the FromSource
+// option, or to VarSet.ParseFrom. It is safe for
+// concurrent use.
+type Tracker struct {
+	src Source
+
+	mu    sync.Mutex
+	found map[string]bool
+}
+
+// Track returns a Tracker wrapping the passed Source, or the process
+// environment if src is nil.
+func Track(src Source) *Tracker {
+	if src == nil {
+		src = Environment
+	}
+	return &Tracker{src: src, found: map[string]bool{}}
+}
+
+// Lookup returns the value of the named variable from the wrapped
+// Source, and whether it is set, recording the lookup.
+func (t *Tracker) Lookup(name string) (string, bool) {
+	value, ok := t.src.Lookup(name)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.found[name] = t.found[name] || ok
+	return value, ok
+}
+
+// Environ returns every variable of the wrapped Source. This is not
+// recorded as a lookup of any of them.
+func (t *Tracker) Environ() []string {
+	return t.src.Environ()
+}
+
+// Locate returns where the wrapped Source defined the named variable,
+// if it can describe that, so that provenance is still recorded
+// through the Tracker.
+func (t *Tracker) Locate(name string) string {
+	if l, ok := t.src.(locator); ok {
+		return l.Locate(name)
+	}
+	return ""
+}
+
+// Read returns the name of every variable that has been looked up,
+// in lexicographical order.
+func (t *Tracker) Read() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	names := make([]string, 0, len(t.found))
+	for name := range t.found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Missing returns the name of every variable that has been looked up
+// but was never set, in lexicographical order. An alias that is
+// not set is included, as each alias is looked up in turn.
+func (t *Tracker) Missing() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var names []string
+	for name, found := range t.found {
+		if !found {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unused returns the name of every variable of the wrapped Source
+// that starts with prefix and has never been looked up, in
+// lexicographical order. These are typically settings left
+// behind in a deployment after the code that read them
+// was removed.
+func (t *Tracker) Unused(prefix string) []string {
+	values := environMap(t.src)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var names []string
+	for name := range values {
+		if _, read := t.found[name]; !read && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
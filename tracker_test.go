@@ -0,0 +1,58 @@
+package envconv_test
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := envconv.Track(envconv.NewEnv(map[string]string{
+		"APP_PORT":      "9090",
+		"APP_OLD_HOST":  "localhost",
+		"APP_LEGACY":    "true",
+		"APP_FORGOTTEN": "1",
+		"HOME":          "/home/app",
+	}))
+	src := envconv.FromSource(tracker)
+
+	envconv.ToInt("APP_PORT", src)
+	envconv.ToString("APP_HOST", src, envconv.Aliases("APP_OLD_HOST"))
+	envconv.ToDurationWithDefault("APP_TIMEOUT", 0, src)
+
+	s := envconv.NewVarSet("test")
+	s.Int("APP_WORKERS", 4, "worker count")
+	assert.NoError(t, s.ParseFrom(tracker), "there should be no error")
+
+	assert.Equal(t, []string{"APP_HOST", "APP_OLD_HOST", "APP_PORT", "APP_TIMEOUT", "APP_WORKERS"}, tracker.Read(), "they should be equal")
+	assert.Equal(t, []string{"APP_HOST", "APP_TIMEOUT", "APP_WORKERS"}, tracker.Missing(), "they should be equal")
+	assert.Equal(t, []string{"APP_FORGOTTEN", "APP_LEGACY"}, tracker.Unused("APP_"), "they should be equal")
+	assert.Equal(t, []string{"APP_FORGOTTEN", "APP_LEGACY", "HOME"}, tracker.Unused(""), "they should be equal")
+
+	t.Run("concurrent", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				envconv.ToBool("APP_LEGACY", src)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, []string{"APP_FORGOTTEN"}, tracker.Unused("APP_"), "they should be equal")
+	})
+}
+
+func TestTrackerLocation(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "PORT=9090\n")
+	src, err := envconv.ReadFileSource(path)
+	assert.NoError(t, err, "there should be no error")
+
+	r := envconv.NewReport()
+	envconv.ToInt("PORT", envconv.FromSource(envconv.Track(src)), envconv.Record(r))
+	p, _ := r.Lookup("PORT")
+	assert.Equal(t, path+":1", p.Location, "they should be equal")
+}
@@ -430,3 +430,19 @@ func TestToUint64SliceWithDefault(t *testing.T) {
 	}
 	runSliceWithDefaultEmptyTest[uint64](t, ",", defaultValue, envconv.ToUint64SliceWithDefault)
 }
+
+func TestToUint16Slice2D(t *testing.T) {
+	testData := []struct {
+		env           string
+		value         string
+		expected      [][]uint16
+		errorExpected bool
+	}{
+		{"TEST_UINT16_SLICE_2D_VALID", "80,443;65535", [][]uint16{{80, 443}, {65535}}, false},
+		{"TEST_UINT16_SLICE_2D_NEGATIVE", "80;-1", [][]uint16{}, true},
+	}
+
+	for _, td := range testData {
+		runSlice2DTest(t, td.env, td.value, td.expected, td.errorExpected, envconv.ToUint16Slice2D)
+	}
+}
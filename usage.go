@@ -0,0 +1,177 @@
+package envconv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// UsageFormat selects how WriteUsage renders the declared variables.
+type UsageFormat int
+
+const (
+	// UsageText renders an indented plain text listing, in the
+	// style of flag.PrintDefaults.
+	UsageText UsageFormat = iota
+
+	// UsageMarkdown renders a Markdown table.
+	UsageMarkdown
+
+	// UsageJSON renders a JSON array, with one object per variable.
+	UsageJSON
+)
+
+// VarUsage describes a single declared variable, as rendered by
+// WriteUsage. Value holds the raw value from the Source the VarSet
+// was last parsed from with ParseFrom, or otherwise from the
+// environment, replaced with [REDACTED] for a secret variable.
+type VarUsage struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Default  string `json:"default"`
+	Required bool   `json:"required"`
+	Secret   bool   `json:"secret"`
+	Set      bool   `json:"set"`
+	Value    string `json:"value,omitempty"`
+	Usage    string `json:"usage"`
+}
+
+// Usage returns a description of each declared variable, in
+// lexicographical order of name.
+func (s *VarSet) Usage() []VarUsage {
+	var usage []VarUsage
+	s.VisitAll(func(v *Var) {
+		value, set := v.raw()
+		if set && v.Secret {
+			value = redacted
+		}
+		usage = append(usage, VarUsage{
+			Name:     v.Name,
+			Type:     v.Type,
+			Default:  v.DefValue,
+			Required: v.Required,
+			Secret:   v.Secret,
+			Set:      set,
+			Value:    value,
+			Usage:    v.Usage,
+		})
+	})
+	return usage
+}
+
+// WriteUsage writes a description of each declared variable to w,
+// in the passed format.
+func (s *VarSet) WriteUsage(w io.Writer, format UsageFormat) error {
+	usage := s.Usage()
+	switch format {
+	case UsageText:
+		return writeUsageText(w, usage)
+	case UsageMarkdown:
+		return writeUsageMarkdown(w, usage)
+	case UsageJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if usage == nil {
+			usage = []VarUsage{}
+		}
+		return encoder.Encode(usage)
+	}
+	return fmt.Errorf("envconv: unknown usage format %d", format)
+}
+
+// PrintDefaults prints a plain text description of each declared
+// variable to the output of the VarSet, which is os.Stderr
+// by default.
+func (s *VarSet) PrintDefaults() {
+	s.WriteUsage(s.Output(), UsageText)
+}
+
+// PrintDefaults prints a plain text description of each variable
+// declared in the default VarSet to os.Stderr.
+func PrintDefaults() {
+	Variables.PrintDefaults()
+}
+
+// writeUsageText writes the passed variable descriptions to w as an
+// indented plain text listing.
+func writeUsageText(w io.Writer, usage []VarUsage) error {
+	var b strings.Builder
+	for _, u := range usage {
+		fmt.Fprintf(&b, "  %s %s", u.Name, u.Type)
+		switch {
+		case u.Required && u.Secret:
+			b.WriteString(" (required, secret)")
+		case u.Required:
+			b.WriteString(" (required)")
+		case u.Secret:
+			b.WriteString(" (secret)")
+		}
+		b.WriteString("\n    \t")
+		if u.Usage != "" {
+			b.WriteString(strings.ReplaceAll(u.Usage, "\n", "\n    \t"))
+			b.WriteString(" ")
+		}
+
+		var details []string
+		switch {
+		case u.Required:
+		case u.Secret && u.Default != "":
+			details = append(details, "default "+u.Default)
+		default:
+			details = append(details, fmt.Sprintf("default %q", u.Default))
+		}
+		switch {
+		case !u.Set:
+			details = append(details, "not set")
+		case u.Secret:
+			details = append(details, "current "+u.Value)
+		default:
+			details = append(details, fmt.Sprintf("current %q", u.Value))
+		}
+		fmt.Fprintf(&b, "(%s)\n", strings.Join(details, ", "))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownReplacer escapes the characters that would break a
+// Markdown table cell.
+var markdownReplacer = strings.NewReplacer("|", `\|`, "\n", "<br>")
+
+// writeUsageMarkdown writes the passed variable descriptions to w as
+// a Markdown table.
+func writeUsageMarkdown(w io.Writer, usage []VarUsage) error {
+	var b strings.Builder
+	b.WriteString("| Name | Type | Default | Required | Value | Description |\n")
+	b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+	for _, u := range usage {
+		required := "no"
+		if u.Required {
+			required = "yes"
+		}
+		value := "*not set*"
+		if u.Set {
+			value = markdownCode(u.Value)
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s |\n",
+			markdownCode(u.Name),
+			markdownReplacer.Replace(u.Type),
+			markdownCode(u.Default),
+			required,
+			value,
+			markdownReplacer.Replace(u.Usage),
+		)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownCode formats the passed text as an inline Markdown code
+// span, suitable for use in a table cell.
+func markdownCode(text string) string {
+	if text == "" {
+		return ""
+	}
+	return "`" + markdownReplacer.Replace(text) + "`"
+}
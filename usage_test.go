@@ -0,0 +1,137 @@
+package envconv_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUsageVarSet returns a VarSet declaring a representative mix of
+// variables for the usage tests.
+func newUsageVarSet() *envconv.VarSet {
+	os.Setenv("TEST_USAGE_PORT", "9090")
+	os.Setenv("TEST_USAGE_PASSWORD", "hunter2")
+	os.Unsetenv("TEST_USAGE_TIMEOUT")
+
+	s := envconv.NewVarSet("test")
+	s.Int("TEST_USAGE_PORT", 8080, "listen port")
+	s.String("TEST_USAGE_PASSWORD", "", "database | password", envconv.Required(), envconv.Redact())
+	s.Duration("TEST_USAGE_TIMEOUT", 5*time.Second, "request timeout")
+	return s
+}
+
+func TestWriteUsageText(t *testing.T) {
+	var b bytes.Buffer
+	s := newUsageVarSet()
+	s.SetOutput(&b)
+	s.PrintDefaults()
+
+	assert.Equal(t, `  TEST_USAGE_PASSWORD string (required, secret)
+    	database | password (current [REDACTED])
+  TEST_USAGE_PORT int
+    	listen port (default "8080", current "9090")
+  TEST_USAGE_TIMEOUT time.Duration
+    	request timeout (default "5s", not set)
+`, b.String(), "they should be equal")
+}
+
+func TestWriteUsageMarkdown(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, newUsageVarSet().WriteUsage(&b, envconv.UsageMarkdown), "there should be no error")
+
+	assert.Equal(t, "| Name | Type | Default | Required | Value | Description |\n"+
+		"| --- | --- | --- | --- | --- | --- |\n"+
+		"| `TEST_USAGE_PASSWORD` | string |  | yes | `[REDACTED]` | database \\| password |\n"+
+		"| `TEST_USAGE_PORT` | int | `8080` | no | `9090` | listen port |\n"+
+		"| `TEST_USAGE_TIMEOUT` | time.Duration | `5s` | no | *not set* | request timeout |\n",
+		b.String(), "they should be equal")
+}
+
+func TestWriteUsageJSON(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, newUsageVarSet().WriteUsage(&b, envconv.UsageJSON), "there should be no error")
+	assert.NotContains(t, b.String(), "hunter2")
+
+	var usage []envconv.VarUsage
+	assert.NoError(t, json.Unmarshal(b.Bytes(), &usage), "there should be no error")
+	assert.Equal(t, []envconv.VarUsage{
+		{Name: "TEST_USAGE_PASSWORD", Type: "string", Required: true, Secret: true, Set: true, Value: "[REDACTED]", Usage: "database | password"},
+		{Name: "TEST_USAGE_PORT", Type: "int", Default: "8080", Set: true, Value: "9090", Usage: "listen port"},
+		{Name: "TEST_USAGE_TIMEOUT", Type: "time.Duration", Default: "5s", Usage: "request timeout"},
+	}, usage, "they should be equal")
+}
+
+func TestUsageSecretDefault(t *testing.T) {
+	os.Unsetenv("TEST_USAGE_SECRET_TOKEN")
+	os.Unsetenv("TEST_USAGE_SECRET_PASSWORD")
+
+	var cfg struct {
+		Token string `env:"TOKEN" secret:"true" default:"s3cr3t"`
+	}
+	s := envconv.NewVarSet("test")
+	assert.NoError(t, s.Struct(&cfg, envconv.Prefix("TEST_USAGE_SECRET_")), "there should be no error")
+	s.String("TEST_USAGE_SECRET_PASSWORD", "hunter2", "database password", envconv.Redact())
+
+	var b bytes.Buffer
+	s.SetOutput(&b)
+	s.PrintDefaults()
+	assert.Equal(t, `  TEST_USAGE_SECRET_PASSWORD string (secret)
+    	database password (default [REDACTED], not set)
+  TEST_USAGE_SECRET_TOKEN string (secret)
+    	(default [REDACTED], not set)
+`, b.String(), "they should be equal")
+
+	for _, format := range []envconv.UsageFormat{envconv.UsageMarkdown, envconv.UsageJSON} {
+		b.Reset()
+		assert.NoError(t, s.WriteUsage(&b, format), "there should be no error")
+		assert.NotContains(t, b.String(), "s3cr3t", "the default should be redacted")
+		assert.NotContains(t, b.String(), "hunter2", "the default should be redacted")
+	}
+
+	b.Reset()
+	assert.NoError(t, s.WriteEnvExample(&b, nil), "there should be no error")
+	assert.Contains(t, b.String(), "# (string, default [REDACTED], secret)\nTEST_USAGE_SECRET_PASSWORD=\n")
+	assert.Contains(t, b.String(), "# (string, default [REDACTED], secret)\nTEST_USAGE_SECRET_TOKEN=\n")
+	assert.Equal(t, "[REDACTED]", s.Lookup("TEST_USAGE_SECRET_TOKEN").DefValue, "they should be equal")
+	assert.Equal(t, "s3cr3t", cfg.Token, "the field should hold the default")
+}
+
+func TestUsageFromStruct(t *testing.T) {
+	os.Setenv("TEST_USAGE_STRUCT_PORT", "7070")
+	os.Unsetenv("TEST_USAGE_STRUCT_HOST")
+
+	var cfg struct {
+		Port int    `env:"PORT" default:"8080" usage:"listen port"`
+		Host string `env:"HOST" usage:"host to bind"`
+	}
+	s := envconv.NewVarSet("test")
+	assert.NoError(t, s.Struct(&cfg, envconv.Prefix("TEST_USAGE_STRUCT_")), "there should be no error")
+
+	src := envconv.NewEnv(map[string]string{"TEST_USAGE_STRUCT_HOST": "localhost"})
+	assert.NoError(t, s.ParseFrom(src), "there should be no error")
+	assert.Equal(t, []envconv.VarUsage{
+		{Name: "TEST_USAGE_STRUCT_HOST", Type: "string", Set: true, Value: "localhost", Usage: "host to bind"},
+		{Name: "TEST_USAGE_STRUCT_PORT", Type: "int", Default: "8080", Usage: "listen port"},
+	}, s.Usage(), "the values should come from the Source that was parsed")
+
+	assert.NoError(t, s.Parse(), "there should be no error")
+	assert.Equal(t, []envconv.VarUsage{
+		{Name: "TEST_USAGE_STRUCT_HOST", Type: "string", Usage: "host to bind"},
+		{Name: "TEST_USAGE_STRUCT_PORT", Type: "int", Default: "8080", Set: true, Value: "7070", Usage: "listen port"},
+	}, s.Usage(), "the values should come from the environment")
+}
+
+func TestRequired(t *testing.T) {
+	os.Unsetenv("TEST_REQUIRED_MISSING")
+
+	s := envconv.NewVarSet("test")
+	s.String("TEST_REQUIRED_MISSING", "default", "", envconv.Required())
+	err := s.Parse()
+	assert.ErrorIs(t, err, envconv.ErrNotSet)
+	assert.Contains(t, err.Error(), "TEST_REQUIRED_MISSING")
+}
@@ -0,0 +1,135 @@
+package envconv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Value holds the current value of a single environment variable,
+// converted to type T, for a configuration that can change while
+// the program runs. Load is safe to call from any number of
+// goroutines, and is cheap enough for hot paths, while
+// Reload resolves the variable again.
+//
+// A Value keeps its last good value whenever a reload fails,
+// either because the variable cannot be converted or
+// because a validation function rejects it.
+type Value[T any] struct {
+	name         string
+	defaultValue T
+	load         func(string, ...Option) (T, error)
+	opts         []Option
+	required     bool
+
+	current atomic.Pointer[T]
+
+	mu        sync.Mutex
+	validate  []func(T) error
+	listeners []func(old T, new T)
+}
+
+// NewValue returns a Value for the named environment variable, which
+// holds the passed default value until the first successful Reload.
+// The passed load function, which will usually be one of the To*
+// functions, converts the variable, and the passed Options are
+// used on every reload. As with a VarSet, the default value
+// is used whenever the variable is not set, unless the
+// Required option is passed.
+//
+// With ToStruct as the load function, and a prefix as the name, a
+// Value holds a whole configuration struct. Every reload decodes
+// the struct again, and a field whose variable is not set takes
+// its default, while a required field that is not set fails
+// the reload rather than falling back to the default value.
+func NewValue[T any](varName string, value T, load func(string, ...Option) (T, error), opts ...Option) *Value[T] {
+	v := &Value[T]{
+		name:         varName,
+		defaultValue: value,
+		load:         load,
+		opts:         opts,
+		required:     newOptions(opts).required,
+	}
+	v.current.Store(&value)
+	return v
+}
+
+// Name returns the name of the environment variable.
+func (v *Value[T]) Name() string {
+	return v.name
+}
+
+// Load returns the current value.
+func (v *Value[T]) Load() T {
+	return *v.current.Load()
+}
+
+// Validate adds a function that every new value must pass before it
+// replaces the current one. A Reload that is rejected by any of
+// these functions returns its error, prefixed with the
+// variable name.
+func (v *Value[T]) Validate(fn func(T) error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.validate = append(v.validate, fn)
+}
+
+// OnChange adds a function to be called with the old and new value
+// whenever a Reload replaces the current value with a different
+// one. The functions are called in the order in which they
+// were added, from the goroutine that called Reload, and
+// must not call Reload themselves.
+func (v *Value[T]) OnChange(fn func(old T, new T)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.listeners = append(v.listeners, fn)
+}
+
+// Reload resolves the environment variable again and, if it converts
+// and passes every validation function, makes it the current value.
+// Otherwise the current value is kept and the error is returned.
+func (v *Value[T]) Reload() error {
+	return v.reload(nil)
+}
+
+// ReloadFrom is like Reload, but loads the variable from src. It can
+// be passed to NewWatcher as the apply function.
+func (v *Value[T]) ReloadFrom(src Source) error {
+	return v.reload(src)
+}
+
+// reload implements Reload and ReloadFrom, loading from src
+// if it is not nil.
+func (v *Value[T]) reload(src Source) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	opts := v.opts
+	if src != nil {
+		opts = append(opts[:len(opts):len(opts)], FromSource(src))
+	}
+	value, err := v.load(v.name, opts...)
+	var notSet *NotSetError
+	if errors.As(err, &notSet) && notSet.VarName == v.name && !v.required {
+		value, err = v.defaultValue, nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, fn := range v.validate {
+		if err := fn(value); err != nil {
+			return fmt.Errorf("%s: %w", v.name, err)
+		}
+	}
+
+	old := v.current.Swap(&value)
+	if reflect.DeepEqual(*old, value) {
+		return nil
+	}
+	for _, fn := range v.listeners {
+		fn(*old, value)
+	}
+	return nil
+}
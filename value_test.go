@@ -0,0 +1,129 @@
+package envconv_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+// mapSource is a Source backed by a map, for the tests.
+type mapSource map[string]string
+
+func (m mapSource) Lookup(name string) (string, bool) {
+	v, ok := m[name]
+	return v, ok
+}
+
+func (m mapSource) Environ() []string {
+	var env []string
+	for name, value := range m {
+		env = append(env, name+"="+value)
+	}
+	return env
+}
+
+func TestValue(t *testing.T) {
+	os.Setenv("TEST_VALUE_RATE", "10")
+
+	rate := envconv.NewValue("TEST_VALUE_RATE", 1, envconv.ToInt)
+	rate.Validate(func(n int) error {
+		if n <= 0 {
+			return errors.New("must be positive")
+		}
+		return nil
+	})
+	var changes []string
+	rate.OnChange(func(old, new int) {
+		changes = append(changes, fmt.Sprintf("%d->%d", old, new))
+	})
+	assert.Equal(t, 1, rate.Load(), "they should be equal")
+
+	assert.NoError(t, rate.Reload(), "there should be no error")
+	assert.Equal(t, 10, rate.Load(), "they should be equal")
+
+	t.Run("unchanged", func(t *testing.T) {
+		assert.NoError(t, rate.Reload(), "there should be no error")
+		assert.Equal(t, []string{"1->10"}, changes, "they should be equal")
+	})
+
+	t.Run("invalid conversion", func(t *testing.T) {
+		os.Setenv("TEST_VALUE_RATE", "fast")
+		var conversionError *envconv.ConversionError
+		assert.ErrorAs(t, rate.Reload(), &conversionError)
+		assert.Equal(t, 10, rate.Load(), "they should be equal")
+	})
+
+	t.Run("failed validation", func(t *testing.T) {
+		os.Setenv("TEST_VALUE_RATE", "-5")
+		assert.EqualError(t, rate.Reload(), "TEST_VALUE_RATE: must be positive")
+		assert.Equal(t, 10, rate.Load(), "they should be equal")
+	})
+
+	t.Run("not set", func(t *testing.T) {
+		os.Unsetenv("TEST_VALUE_RATE")
+		assert.NoError(t, rate.Reload(), "there should be no error")
+		assert.Equal(t, 1, rate.Load(), "they should be equal")
+		assert.Equal(t, []string{"1->10", "10->1"}, changes, "they should be equal")
+	})
+
+	t.Run("required", func(t *testing.T) {
+		v := envconv.NewValue("TEST_VALUE_RATE", 1, envconv.ToInt, envconv.Required())
+		assert.ErrorIs(t, v.Reload(), envconv.ErrNotSet)
+		assert.Equal(t, 1, v.Load(), "they should be equal")
+	})
+}
+
+func TestValueReloadFrom(t *testing.T) {
+	load := func(varName string, opts ...envconv.Option) ([]string, error) {
+		return envconv.ToStringSlice(varName, ",", opts...)
+	}
+	hosts := envconv.NewValue("TEST_VALUE_HOSTS", []string{"localhost"}, load)
+
+	assert.NoError(t, hosts.ReloadFrom(mapSource{"TEST_VALUE_HOSTS": "a,b"}), "there should be no error")
+	assert.Equal(t, []string{"a", "b"}, hosts.Load(), "they should be equal")
+}
+
+func TestValueConcurrent(t *testing.T) {
+	sources := []envconv.Source{
+		mapSource{"TEST_VALUE_CONCURRENT": "1s"},
+		mapSource{"TEST_VALUE_CONCURRENT": "2s"},
+		mapSource{"TEST_VALUE_CONCURRENT": "invalid"},
+	}
+	timeout := envconv.NewValue("TEST_VALUE_CONCURRENT", 0, envconv.ToDuration)
+	var mu sync.Mutex
+	changes := 0
+	timeout.OnChange(func(_, _ time.Duration) {
+		mu.Lock()
+		changes++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				timeout.ReloadFrom(sources[j%len(sources)])
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				d := timeout.Load()
+				assert.Contains(t, []time.Duration{0, time.Second, 2 * time.Second}, d)
+			}
+		}()
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Positive(t, changes)
+}
@@ -0,0 +1,922 @@
+package envconv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Var represents a single environment variable declared in a VarSet.
+type Var struct {
+	Name     string // name of the environment variable
+	Usage    string // help message
+	Type     string // name of the type the variable converts to, such as "int"
+	DefValue string // default value, as text, redacted for a secret
+	Required bool   // whether the Required option was used
+	Secret   bool   // whether the Redact option was used
+
+	opts   *options
+	group  string        // prefix of the struct the variable was declared from
+	mu     *sync.RWMutex // lock of the VarSet, held while values are set
+	src    Source        // Source of the last parse, nil for Parse
+	parse  func(src Source) (func(), error)
+	get    func() any
+	format func() string
+	encode func() (string, error)
+}
+
+// Get returns the current value of the variable, such as an int for a
+// variable declared with Int. Unlike reading through the pointer that
+// the declaration returned, Get is safe to call while the VarSet
+// is being parsed, for example by a Watcher.
+func (v *Var) Get() any {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.get()
+}
+
+// String returns the current value of the variable, as text. This
+// is the default value until the VarSet has been parsed. Like
+// Get, it is safe to call while the VarSet is being parsed.
+func (v *Var) String() string {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.format()
+}
+
+// raw returns the unconverted value of the variable, and whether it is
+// set, from the Source that the VarSet was last parsed from, or as it
+// was declared if there is none. The lookup is not recorded, so
+// that it does not replace the Provenance recorded by Parse.
+func (v *Var) raw() (string, bool) {
+	o := *v.opts
+	o.report = nil
+	v.mu.RLock()
+	if v.src != nil {
+		o.source = v.src
+	}
+	v.mu.RUnlock()
+	value, err := loadFromEnvironment(v.Name, &o)
+	return value, err == nil
+}
+
+// VarSet represents a set of declared environment variables, in the
+// same way that flag.FlagSet represents a set of command line flags.
+// Each variable is declared with a default value and a usage string,
+// and is filled in from the environment when Parse is called.
+//
+// Parse and ParseFrom set every value while holding a lock, which
+// Var.Get and Var.String, and so Usage, Resolve and Handler, also
+// take. Values may be read through them while another goroutine
+// parses the VarSet, but not through the declared pointers.
+type VarSet struct {
+	mu     sync.RWMutex
+	name   string
+	vars   map[string]*Var
+	parsed bool
+	output io.Writer
+}
+
+// NewVarSet returns a new, empty VarSet with the passed name.
+func NewVarSet(name string) *VarSet {
+	return &VarSet{name: name, vars: map[string]*Var{}}
+}
+
+// Name returns the name of the VarSet.
+func (s *VarSet) Name() string {
+	return s.name
+}
+
+// Output returns the destination for usage messages. It is
+// os.Stderr unless SetOutput has been called.
+func (s *VarSet) Output() io.Writer {
+	if s.output == nil {
+		return os.Stderr
+	}
+	return s.output
+}
+
+// SetOutput sets the destination for usage messages.
+func (s *VarSet) SetOutput(w io.Writer) {
+	s.output = w
+}
+
+// Parse loads every declared variable from the environment. A variable
+// that is not set keeps its default value, unless it was declared
+// with the Required option. Parse returns an error
+// joining every variable that failed to load, rather than
+// stopping at the first.
+func (s *VarSet) Parse() error {
+	var errs []error
+	var sets []func()
+	s.VisitAll(func(v *Var) {
+		set, err := v.parse(nil)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		sets = append(sets, set)
+	})
+	s.apply(sets)
+	return errors.Join(errs...)
+}
+
+// ParseFrom loads every declared variable from src, in the same way
+// that Parse loads them from the environment, except that a variable
+// that is not set in src is reset to its default value. Unlike
+// Parse, no value is changed unless every variable loads, so
+// the VarSet never holds a mix of old and new values. If src is nil,
+// each variable is loaded as it was declared, from the environment
+// unless it was declared with FromSource.
+//
+// As ParseFrom can be passed to a Watcher, which calls it from its
+// own goroutine, the variables should then be read with Var.Get.
+func (s *VarSet) ParseFrom(src Source) error {
+	var errs []error
+	var sets []func()
+	s.VisitAll(func(v *Var) {
+		set, err := v.parse(src)
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		sets = append(sets, set)
+	})
+	if errs != nil {
+		return errors.Join(errs...)
+	}
+	s.apply(sets)
+	return nil
+}
+
+// apply calls each of the passed functions, which set the values of
+// variables, while holding the lock of the VarSet, and marks
+// the VarSet as parsed.
+func (s *VarSet) apply(sets []func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, set := range sets {
+		set()
+	}
+	s.parsed = true
+}
+
+// Encode returns the current value of every declared variable as an
+// Env, formatted with Format so that ParseFrom reads the same values
+// back. The Env can be turned into a list of "key=value" strings
+// with Environ, or into a map with Map. Secret variables are
+// left out unless includeSecrets is true.
+//
+// Each value is formatted for the Options its variable was declared
+// with, so slice elements are only quoted for the Quoted option.
+// A value that those Options would not read back unchanged, such
+// as an element holding the separator of a slice that is not
+// Quoted, or an empty value for a variable whose default
+// is not empty, is an error. Encode returns an error
+// joining every variable that failed to encode.
+func (s *VarSet) Encode(includeSecrets bool) (*Env, error) {
+	values := map[string]string{}
+	var errs []error
+	s.VisitAll(func(v *Var) {
+		if v.Secret && !includeSecrets {
+			return
+		}
+		v.mu.RLock()
+		text, err := v.encode()
+		v.mu.RUnlock()
+		if err != nil {
+			errs = append(errs, err)
+			return
+		}
+		values[v.Name] = text
+	})
+	if errs != nil {
+		return nil, errors.Join(errs...)
+	}
+	return &Env{values: values}, nil
+}
+
+// Parsed reports whether Parse has been called.
+func (s *VarSet) Parsed() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.parsed
+}
+
+// Lookup returns the Var for the named environment variable, or nil
+// if no such variable has been declared.
+func (s *VarSet) Lookup(name string) *Var {
+	return s.vars[name]
+}
+
+// VisitAll calls fn for each declared variable, in lexicographical
+// order of name.
+func (s *VarSet) VisitAll(fn func(*Var)) {
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fn(s.vars[name])
+	}
+}
+
+// binding describes how a declared variable is loaded, where its value
+// is kept and how that value is formatted. Values are passed as any,
+// so that struct fields can be bound through reflection in the
+// same way as the typed pointers returned by declare.
+type binding struct {
+	typ    string
+	value  any
+	load   func(string, ...Option) (any, error)
+	get    func() any
+	set    func(any)
+	format func(any) string
+	encode func(any) (string, error)
+}
+
+// bind adds the variable described by the passed binding to the
+// VarSet, and sets it to its default value.
+func (s *VarSet) bind(name string, usage string, b binding, opts []Option) {
+	if _, exists := s.vars[name]; exists {
+		panic(fmt.Sprintf("envconv: variable redefined: %s", name))
+	}
+
+	o := newOptions(opts)
+	b.set(b.value)
+	defValue := b.format(b.value)
+	if o.redact && defValue != "" {
+		defValue = redacted
+	}
+	v := &Var{
+		Name:     name,
+		Usage:    usage,
+		Type:     b.typ,
+		DefValue: defValue,
+		Required: o.required,
+		Secret:   o.redact,
+		opts:     o,
+		mu:       &s.mu,
+		get:      b.get,
+	}
+	// loaded reports whether the variable holds a value loaded from
+	// the environment, rather than its default, so that a failed
+	// load that leaves the default in place is recorded as such.
+	loaded := false
+	v.parse = func(src Source) (func(), error) {
+		opts := opts
+		if src != nil {
+			opts = append(opts[:len(opts):len(opts)], FromSource(src))
+		}
+		value, err := b.load(name, opts...)
+		isDefault := false
+		switch {
+		case errors.Is(err, ErrNotSet) && !o.required:
+			record(name, OriginDefault, "", b.format(b.value), err, o)
+			value, isDefault = b.value, true
+		case err != nil:
+			s.mu.RLock()
+			wasLoaded := loaded
+			s.mu.RUnlock()
+			if !wasLoaded {
+				record(name, OriginDefault, "", b.format(b.value), nil, o)
+				recordError(name, err, o)
+			}
+			return nil, err
+		}
+		return func() {
+			b.set(value)
+			v.src = src
+			loaded = !isDefault
+		}, nil
+	}
+	v.format = func() string {
+		return b.format(b.get())
+	}
+	v.encode = func() (string, error) {
+		text, err := b.encode(b.get())
+		if err != nil {
+			return "", fmt.Errorf("envconv: cannot encode %s: %w", name, err)
+		}
+		// The text is read back as ParseFrom would read it, so that
+		// a value the declared options cannot represent is an
+		// error rather than a change.
+		from := NewEnv(map[string]string{name: text})
+		value, err := b.load(name, append(opts[:len(opts):len(opts)], FromSource(from), Record(nil))...)
+		switch {
+		case errors.Is(err, ErrNotSet) && !o.required:
+			value = b.value
+		case err != nil:
+			return "", fmt.Errorf("envconv: cannot encode %s: %w", name, err)
+		}
+		if !sameValue(reflect.ValueOf(value), reflect.ValueOf(b.get())) {
+			return "", fmt.Errorf("envconv: cannot encode %s: value would not be read back unchanged", name)
+		}
+		return text, nil
+	}
+	s.vars[name] = v
+}
+
+// sameValue reports whether a value read back by Encode is the same as
+// the value that was encoded. Unlike reflect.DeepEqual, it treats a
+// nil slice as equal to an empty one, and NaN as equal to itself,
+// as Format does not tell them apart.
+func sameValue(a reflect.Value, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() || a.Type() != b.Type() {
+		return a.IsValid() == b.IsValid() && (!a.IsValid() || reflect.DeepEqual(a.Interface(), b.Interface()))
+	}
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !sameValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float() || (math.IsNaN(a.Float()) && math.IsNaN(b.Float()))
+	}
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+// declare adds a variable of type T to the passed VarSet, returning a
+// pointer to its value. The passed load function, which will be one
+// of the To* functions, is used to fill in the value on Parse. The
+// format function describes the value for display, while the
+// encode function formats it for Encode.
+func declare[T any](s *VarSet, name string, value T, usage string, load func(string, ...Option) (T, error), format func(T) string, encode func(T) (string, error), opts []Option) *T {
+	p := new(T)
+	s.bind(name, usage, binding{
+		typ:   typeName[T](),
+		value: value,
+		load: func(varName string, opts ...Option) (any, error) {
+			return load(varName, opts...)
+		},
+		get: func() any {
+			return *p
+		},
+		set: func(v any) {
+			*p = as[T](v)
+		},
+		format: func(v any) string {
+			return format(as[T](v))
+		},
+		encode: func(v any) (string, error) {
+			return encode(as[T](v))
+		},
+	}, opts)
+	return p
+}
+
+// as returns the passed value as type T, or the zero value of T if
+// it is nil.
+func as[T any](v any) T {
+	t, _ := v.(T)
+	return t
+}
+
+// formatValue formats a single value as text.
+func formatValue[T any](value T) string {
+	return fmt.Sprint(value)
+}
+
+// formatter returns a function that formats a value with Format and
+// the passed separator, for Encode.
+func formatter[T any](separator string) func(T) (string, error) {
+	return func(value T) (string, error) {
+		return Format(value, separator)
+	}
+}
+
+// sliceEncoder returns a function that formats each element of a slice
+// with Format, and joins them with the passed separator, for Encode.
+// Unlike Format, it formats a []uint8 as numbers.
+func sliceEncoder[T any](separator string, quoted bool) func([]T) (string, error) {
+	return func(values []T) (string, error) {
+		return encodeSlice(reflect.ValueOf(values), separator, quoted)
+	}
+}
+
+// encodeSlice formats each element of the passed slice with Format,
+// and joins them with the passed separator, using Join if quoted
+// is true.
+func encodeSlice(v reflect.Value, separator string, quoted bool) (string, error) {
+	if separator == "" {
+		return "", errEmptySeparator
+	}
+	elements, err := formatElements(v)
+	if err != nil {
+		return "", err
+	}
+	if !quoted {
+		return strings.Join(elements, separator), nil
+	}
+	return Join(elements, separator), nil
+}
+
+// encodeJSON encodes a value as JSON, for Encode.
+func encodeJSON[T any](value T) (string, error) {
+	b, err := json.Marshal(value)
+	return string(b), err
+}
+
+// sliceFormatter returns a function that formats a slice as text,
+// with each element separated by the passed separator.
+func sliceFormatter[T any](separator string) func([]T) string {
+	return func(values []T) string {
+		elements := make([]string, len(values))
+		for i, v := range values {
+			elements[i] = fmt.Sprint(v)
+		}
+		return strings.Join(elements, separator)
+	}
+}
+
+// Bool declares a bool environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Bool(name string, value bool, usage string, opts ...Option) *bool {
+	return declare(s, name, value, usage, ToBool, formatValue[bool], formatter[bool](""), opts)
+}
+
+// Byte declares a byte environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Byte(name string, value byte, usage string, opts ...Option) *byte {
+	return declare(s, name, value, usage, ToByte, formatValue[byte], formatter[byte](""), opts)
+}
+
+// Duration declares a time.Duration environment variable with the passed
+// name, default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Duration(name string, value time.Duration, usage string, opts ...Option) *time.Duration {
+	return declare(s, name, value, usage, ToDuration, formatValue[time.Duration], formatter[time.Duration](""), opts)
+}
+
+// Float32 declares a float32 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Float32(name string, value float32, usage string, opts ...Option) *float32 {
+	return declare(s, name, value, usage, ToFloat32, formatValue[float32], formatter[float32](""), opts)
+}
+
+// Float64 declares a float64 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Float64(name string, value float64, usage string, opts ...Option) *float64 {
+	return declare(s, name, value, usage, ToFloat64, formatValue[float64], formatter[float64](""), opts)
+}
+
+// Int declares an int environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Int(name string, value int, usage string, opts ...Option) *int {
+	return declare(s, name, value, usage, ToInt, formatValue[int], formatter[int](""), opts)
+}
+
+// Int8 declares an int8 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Int8(name string, value int8, usage string, opts ...Option) *int8 {
+	return declare(s, name, value, usage, ToInt8, formatValue[int8], formatter[int8](""), opts)
+}
+
+// Int16 declares an int16 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Int16(name string, value int16, usage string, opts ...Option) *int16 {
+	return declare(s, name, value, usage, ToInt16, formatValue[int16], formatter[int16](""), opts)
+}
+
+// Int32 declares an int32 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Int32(name string, value int32, usage string, opts ...Option) *int32 {
+	return declare(s, name, value, usage, ToInt32, formatValue[int32], formatter[int32](""), opts)
+}
+
+// Int64 declares an int64 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Int64(name string, value int64, usage string, opts ...Option) *int64 {
+	return declare(s, name, value, usage, ToInt64, formatValue[int64], formatter[int64](""), opts)
+}
+
+// String declares a string environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) String(name string, value string, usage string, opts ...Option) *string {
+	return declare(s, name, value, usage, ToString, formatValue[string], formatter[string](""), opts)
+}
+
+// Uint declares a uint environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Uint(name string, value uint, usage string, opts ...Option) *uint {
+	return declare(s, name, value, usage, ToUint, formatValue[uint], formatter[uint](""), opts)
+}
+
+// Uint8 declares a uint8 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Uint8(name string, value uint8, usage string, opts ...Option) *uint8 {
+	return declare(s, name, value, usage, ToUint8, formatValue[uint8], formatter[uint8](""), opts)
+}
+
+// Uint16 declares a uint16 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Uint16(name string, value uint16, usage string, opts ...Option) *uint16 {
+	return declare(s, name, value, usage, ToUint16, formatValue[uint16], formatter[uint16](""), opts)
+}
+
+// Uint32 declares a uint32 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Uint32(name string, value uint32, usage string, opts ...Option) *uint32 {
+	return declare(s, name, value, usage, ToUint32, formatValue[uint32], formatter[uint32](""), opts)
+}
+
+// Uint64 declares a uint64 environment variable with the passed name,
+// default value and usage string. The returned pointer will
+// hold the value of the variable once Parse is called.
+func (s *VarSet) Uint64(name string, value uint64, usage string, opts ...Option) *uint64 {
+	return declare(s, name, value, usage, ToUint64, formatValue[uint64], formatter[uint64](""), opts)
+}
+
+// ByteSlice declares a []byte environment variable with the passed
+// name, default value and usage string. The returned pointer will
+// hold the raw bytes of the variable once Parse is called.
+func (s *VarSet) ByteSlice(name string, value []byte, usage string, opts ...Option) *[]byte {
+	return declare(s, name, value, usage, ToByteSlice, formatBytes, formatter[[]byte](""), opts)
+}
+
+// BoolSlice declares a []bool environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) BoolSlice(name string, separator string, value []bool, usage string, opts ...Option) *[]bool {
+	return declareSlice(s, name, separator, value, usage, ToBoolSlice, opts)
+}
+
+// DurationSlice declares a []time.Duration environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) DurationSlice(name string, separator string, value []time.Duration, usage string, opts ...Option) *[]time.Duration {
+	return declareSlice(s, name, separator, value, usage, ToDurationSlice, opts)
+}
+
+// Float32Slice declares a []float32 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Float32Slice(name string, separator string, value []float32, usage string, opts ...Option) *[]float32 {
+	return declareSlice(s, name, separator, value, usage, ToFloat32Slice, opts)
+}
+
+// Float64Slice declares a []float64 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Float64Slice(name string, separator string, value []float64, usage string, opts ...Option) *[]float64 {
+	return declareSlice(s, name, separator, value, usage, ToFloat64Slice, opts)
+}
+
+// IntSlice declares a []int environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) IntSlice(name string, separator string, value []int, usage string, opts ...Option) *[]int {
+	return declareSlice(s, name, separator, value, usage, ToIntSlice, opts)
+}
+
+// Int8Slice declares a []int8 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Int8Slice(name string, separator string, value []int8, usage string, opts ...Option) *[]int8 {
+	return declareSlice(s, name, separator, value, usage, ToInt8Slice, opts)
+}
+
+// Int16Slice declares a []int16 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Int16Slice(name string, separator string, value []int16, usage string, opts ...Option) *[]int16 {
+	return declareSlice(s, name, separator, value, usage, ToInt16Slice, opts)
+}
+
+// Int32Slice declares a []int32 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Int32Slice(name string, separator string, value []int32, usage string, opts ...Option) *[]int32 {
+	return declareSlice(s, name, separator, value, usage, ToInt32Slice, opts)
+}
+
+// Int64Slice declares a []int64 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Int64Slice(name string, separator string, value []int64, usage string, opts ...Option) *[]int64 {
+	return declareSlice(s, name, separator, value, usage, ToInt64Slice, opts)
+}
+
+// StringSlice declares a []string environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) StringSlice(name string, separator string, value []string, usage string, opts ...Option) *[]string {
+	return declareSlice(s, name, separator, value, usage, ToStringSlice, opts)
+}
+
+// UintSlice declares a []uint environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) UintSlice(name string, separator string, value []uint, usage string, opts ...Option) *[]uint {
+	return declareSlice(s, name, separator, value, usage, ToUintSlice, opts)
+}
+
+// Uint8Slice declares a []uint8 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Uint8Slice(name string, separator string, value []uint8, usage string, opts ...Option) *[]uint8 {
+	return declareSlice(s, name, separator, value, usage, ToUint8Slice, opts)
+}
+
+// Uint16Slice declares a []uint16 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Uint16Slice(name string, separator string, value []uint16, usage string, opts ...Option) *[]uint16 {
+	return declareSlice(s, name, separator, value, usage, ToUint16Slice, opts)
+}
+
+// Uint32Slice declares a []uint32 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Uint32Slice(name string, separator string, value []uint32, usage string, opts ...Option) *[]uint32 {
+	return declareSlice(s, name, separator, value, usage, ToUint32Slice, opts)
+}
+
+// Uint64Slice declares a []uint64 environment variable with the passed
+// name, separator, default value and usage string. The returned
+// pointer will hold the value of the variable once Parse
+// is called.
+func (s *VarSet) Uint64Slice(name string, separator string, value []uint64, usage string, opts ...Option) *[]uint64 {
+	return declareSlice(s, name, separator, value, usage, ToUint64Slice, opts)
+}
+
+// DeclareJSON declares an environment variable in the passed VarSet
+// holding a JSON value, which is decoded into type T with ToJSON.
+// The returned pointer will hold the value of the variable
+// once Parse is called.
+func DeclareJSON[T any](s *VarSet, name string, value T, usage string, opts ...Option) *T {
+	return declare(s, name, value, usage, ToJSON[T], formatJSON[T], encodeJSON[T], opts)
+}
+
+// declareSlice adds a slice variable to the passed VarSet, which is
+// loaded by the passed slice function with the passed separator.
+func declareSlice[T any](s *VarSet, name string, separator string, value []T, usage string, load func(string, string, ...Option) ([]T, error), opts []Option) *[]T {
+	loadSlice := func(varName string, opts ...Option) ([]T, error) {
+		return load(varName, separator, opts...)
+	}
+	return declare(s, name, value, usage, loadSlice, sliceFormatter[T](separator), sliceEncoder[T](separator, newOptions(opts).quoted), opts)
+}
+
+// formatBytes formats a byte slice as text.
+func formatBytes(value []byte) string {
+	return string(value)
+}
+
+// formatJSON formats a value as text, encoded as JSON.
+func formatJSON[T any](value T) string {
+	text, _ := encodeJSON(value)
+	return text
+}
+
+// Variables is the default set of declared environment variables, used
+// by the top level Bool, Int, Parse and VisitAll functions.
+var Variables = NewVarSet("environment")
+
+// Bool declares a bool environment variable in the default VarSet.
+func Bool(name string, value bool, usage string, opts ...Option) *bool {
+	return Variables.Bool(name, value, usage, opts...)
+}
+
+// Byte declares a byte environment variable in the default VarSet.
+func Byte(name string, value byte, usage string, opts ...Option) *byte {
+	return Variables.Byte(name, value, usage, opts...)
+}
+
+// Duration declares a time.Duration environment variable in the
+// default VarSet.
+func Duration(name string, value time.Duration, usage string, opts ...Option) *time.Duration {
+	return Variables.Duration(name, value, usage, opts...)
+}
+
+// Float32 declares a float32 environment variable in the
+// default VarSet.
+func Float32(name string, value float32, usage string, opts ...Option) *float32 {
+	return Variables.Float32(name, value, usage, opts...)
+}
+
+// Float64 declares a float64 environment variable in the default VarSet.
+func Float64(name string, value float64, usage string, opts ...Option) *float64 {
+	return Variables.Float64(name, value, usage, opts...)
+}
+
+// Int declares an int environment variable in the default VarSet.
+func Int(name string, value int, usage string, opts ...Option) *int {
+	return Variables.Int(name, value, usage, opts...)
+}
+
+// Int8 declares an int8 environment variable in the default VarSet.
+func Int8(name string, value int8, usage string, opts ...Option) *int8 {
+	return Variables.Int8(name, value, usage, opts...)
+}
+
+// Int16 declares an int16 environment variable in the default VarSet.
+func Int16(name string, value int16, usage string, opts ...Option) *int16 {
+	return Variables.Int16(name, value, usage, opts...)
+}
+
+// Int32 declares an int32 environment variable in the default VarSet.
+func Int32(name string, value int32, usage string, opts ...Option) *int32 {
+	return Variables.Int32(name, value, usage, opts...)
+}
+
+// Int64 declares an int64 environment variable in the default VarSet.
+func Int64(name string, value int64, usage string, opts ...Option) *int64 {
+	return Variables.Int64(name, value, usage, opts...)
+}
+
+// String declares a string environment variable in the default VarSet.
+func String(name string, value string, usage string, opts ...Option) *string {
+	return Variables.String(name, value, usage, opts...)
+}
+
+// Uint declares a uint environment variable in the default VarSet.
+func Uint(name string, value uint, usage string, opts ...Option) *uint {
+	return Variables.Uint(name, value, usage, opts...)
+}
+
+// Uint8 declares a uint8 environment variable in the default VarSet.
+func Uint8(name string, value uint8, usage string, opts ...Option) *uint8 {
+	return Variables.Uint8(name, value, usage, opts...)
+}
+
+// Uint16 declares a uint16 environment variable in the default VarSet.
+func Uint16(name string, value uint16, usage string, opts ...Option) *uint16 {
+	return Variables.Uint16(name, value, usage, opts...)
+}
+
+// Uint32 declares a uint32 environment variable in the default VarSet.
+func Uint32(name string, value uint32, usage string, opts ...Option) *uint32 {
+	return Variables.Uint32(name, value, usage, opts...)
+}
+
+// Uint64 declares a uint64 environment variable in the default VarSet.
+func Uint64(name string, value uint64, usage string, opts ...Option) *uint64 {
+	return Variables.Uint64(name, value, usage, opts...)
+}
+
+// ByteSlice declares a []byte environment variable in the
+// default VarSet.
+func ByteSlice(name string, value []byte, usage string, opts ...Option) *[]byte {
+	return Variables.ByteSlice(name, value, usage, opts...)
+}
+
+// BoolSlice declares a []bool environment variable in the
+// default VarSet.
+func BoolSlice(name string, separator string, value []bool, usage string, opts ...Option) *[]bool {
+	return Variables.BoolSlice(name, separator, value, usage, opts...)
+}
+
+// DurationSlice declares a []time.Duration environment variable in the
+// default VarSet.
+func DurationSlice(name string, separator string, value []time.Duration, usage string, opts ...Option) *[]time.Duration {
+	return Variables.DurationSlice(name, separator, value, usage, opts...)
+}
+
+// Float32Slice declares a []float32 environment variable in the
+// default VarSet.
+func Float32Slice(name string, separator string, value []float32, usage string, opts ...Option) *[]float32 {
+	return Variables.Float32Slice(name, separator, value, usage, opts...)
+}
+
+// Float64Slice declares a []float64 environment variable in the
+// default VarSet.
+func Float64Slice(name string, separator string, value []float64, usage string, opts ...Option) *[]float64 {
+	return Variables.Float64Slice(name, separator, value, usage, opts...)
+}
+
+// IntSlice declares a []int environment variable in the default VarSet.
+func IntSlice(name string, separator string, value []int, usage string, opts ...Option) *[]int {
+	return Variables.IntSlice(name, separator, value, usage, opts...)
+}
+
+// Int8Slice declares a []int8 environment variable in the
+// default VarSet.
+func Int8Slice(name string, separator string, value []int8, usage string, opts ...Option) *[]int8 {
+	return Variables.Int8Slice(name, separator, value, usage, opts...)
+}
+
+// Int16Slice declares a []int16 environment variable in the
+// default VarSet.
+func Int16Slice(name string, separator string, value []int16, usage string, opts ...Option) *[]int16 {
+	return Variables.Int16Slice(name, separator, value, usage, opts...)
+}
+
+// Int32Slice declares a []int32 environment variable in the
+// default VarSet.
+func Int32Slice(name string, separator string, value []int32, usage string, opts ...Option) *[]int32 {
+	return Variables.Int32Slice(name, separator, value, usage, opts...)
+}
+
+// Int64Slice declares a []int64 environment variable in the
+// default VarSet.
+func Int64Slice(name string, separator string, value []int64, usage string, opts ...Option) *[]int64 {
+	return Variables.Int64Slice(name, separator, value, usage, opts...)
+}
+
+// StringSlice declares a []string environment variable in the
+// default VarSet.
+func StringSlice(name string, separator string, value []string, usage string, opts ...Option) *[]string {
+	return Variables.StringSlice(name, separator, value, usage, opts...)
+}
+
+// UintSlice declares a []uint environment variable in the
+// default VarSet.
+func UintSlice(name string, separator string, value []uint, usage string, opts ...Option) *[]uint {
+	return Variables.UintSlice(name, separator, value, usage, opts...)
+}
+
+// Uint8Slice declares a []uint8 environment variable in the
+// default VarSet.
+func Uint8Slice(name string, separator string, value []uint8, usage string, opts ...Option) *[]uint8 {
+	return Variables.Uint8Slice(name, separator, value, usage, opts...)
+}
+
+// Uint16Slice declares a []uint16 environment variable in the
+// default VarSet.
+func Uint16Slice(name string, separator string, value []uint16, usage string, opts ...Option) *[]uint16 {
+	return Variables.Uint16Slice(name, separator, value, usage, opts...)
+}
+
+// Uint32Slice declares a []uint32 environment variable in the
+// default VarSet.
+func Uint32Slice(name string, separator string, value []uint32, usage string, opts ...Option) *[]uint32 {
+	return Variables.Uint32Slice(name, separator, value, usage, opts...)
+}
+
+// Uint64Slice declares a []uint64 environment variable in the
+// default VarSet.
+func Uint64Slice(name string, separator string, value []uint64, usage string, opts ...Option) *[]uint64 {
+	return Variables.Uint64Slice(name, separator, value, usage, opts...)
+}
+
+// JSON declares an environment variable holding a JSON value in the
+// default VarSet.
+func JSON[T any](name string, value T, usage string, opts ...Option) *T {
+	return DeclareJSON(Variables, name, value, usage, opts...)
+}
+
+// Parse loads every variable declared in the default VarSet from
+// the environment.
+func Parse() error {
+	return Variables.Parse()
+}
+
+// Parsed reports whether the default VarSet has been parsed.
+func Parsed() bool {
+	return Variables.Parsed()
+}
+
+// Lookup returns the Var for the named environment variable declared
+// in the default VarSet, or nil if there is no such variable.
+func Lookup(name string) *Var {
+	return Variables.Lookup(name)
+}
+
+// VisitAll calls fn for each variable declared in the default VarSet,
+// in lexicographical order of name.
+func VisitAll(fn func(*Var)) {
+	Variables.VisitAll(fn)
+}
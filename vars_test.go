@@ -0,0 +1,161 @@
+package envconv_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarSet(t *testing.T) {
+	os.Setenv("TEST_VARSET_PORT", "9090")
+	os.Setenv("TEST_VARSET_DEBUG", "true")
+	os.Setenv("TEST_VARSET_HOSTS", "a, b")
+	os.Unsetenv("TEST_VARSET_TIMEOUT")
+
+	s := envconv.NewVarSet("test")
+	port := s.Int("TEST_VARSET_PORT", 8080, "listen port")
+	debug := s.Bool("TEST_VARSET_DEBUG", false, "enable debug output")
+	timeout := s.Duration("TEST_VARSET_TIMEOUT", 5*time.Second, "request timeout")
+	hosts := s.StringSlice("TEST_VARSET_HOSTS", ",", []string{"localhost"}, "upstream hosts")
+
+	assert.Equal(t, 8080, *port, "they should be equal")
+	assert.False(t, s.Parsed(), "it should not be parsed")
+
+	assert.NoError(t, s.Parse(), "there should be no error")
+	assert.True(t, s.Parsed(), "it should be parsed")
+	assert.Equal(t, 9090, *port, "they should be equal")
+	assert.Equal(t, true, *debug, "they should be equal")
+	assert.Equal(t, 5*time.Second, *timeout, "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, *hosts, "they should be equal")
+}
+
+func TestVarSetParseErrors(t *testing.T) {
+	os.Setenv("TEST_VARSET_ERRORS_PORT", "eighty")
+	os.Setenv("TEST_VARSET_ERRORS_RATE", "fast")
+	os.Setenv("TEST_VARSET_ERRORS_NAME", "valid")
+
+	s := envconv.NewVarSet("test")
+	port := s.Int("TEST_VARSET_ERRORS_PORT", 8080, "listen port")
+	rate := s.Float64("TEST_VARSET_ERRORS_RATE", 1.5, "requests per second")
+	name := s.String("TEST_VARSET_ERRORS_NAME", "default", "service name")
+
+	err := s.Parse()
+	assert.Error(t, err, "there should be an error")
+	assert.Contains(t, err.Error(), "TEST_VARSET_ERRORS_PORT")
+	assert.Contains(t, err.Error(), "TEST_VARSET_ERRORS_RATE")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+
+	assert.Equal(t, 8080, *port, "they should be equal")
+	assert.Equal(t, 1.5, *rate, "they should be equal")
+	assert.Equal(t, "valid", *name, "they should be equal")
+}
+
+func TestVarSetVisitAll(t *testing.T) {
+	os.Setenv("TEST_VARSET_VISIT_B", "2")
+	os.Unsetenv("TEST_VARSET_VISIT_A")
+
+	s := envconv.NewVarSet("test")
+	s.Duration("TEST_VARSET_VISIT_A", time.Minute, "first")
+	s.Uint("TEST_VARSET_VISIT_B", 1, "second")
+	s.IntSlice("TEST_VARSET_VISIT_C", ";", []int{1, 2}, "third")
+	assert.NoError(t, s.Parse(), "there should be no error")
+
+	type visited struct {
+		name, usage, typ, defValue, value string
+	}
+	var vars []visited
+	s.VisitAll(func(v *envconv.Var) {
+		vars = append(vars, visited{v.Name, v.Usage, v.Type, v.DefValue, v.String()})
+	})
+
+	assert.Equal(t, []visited{
+		{"TEST_VARSET_VISIT_A", "first", "time.Duration", "1m0s", "1m0s"},
+		{"TEST_VARSET_VISIT_B", "second", "uint", "1", "2"},
+		{"TEST_VARSET_VISIT_C", "third", "[]int", "1;2", "1;2"},
+	}, vars, "they should be equal")
+
+	assert.Equal(t, "second", s.Lookup("TEST_VARSET_VISIT_B").Usage, "they should be equal")
+	assert.Nil(t, s.Lookup("TEST_VARSET_VISIT_D"), "it should be nil")
+}
+
+func TestVarSetTypes(t *testing.T) {
+	type limits struct {
+		Burst int `json:"burst"`
+	}
+	src := envconv.NewEnv(map[string]string{
+		"LEVEL":   "-3",
+		"PORT":    "8443",
+		"RATIO":   "0.75",
+		"MASK":    "255",
+		"TOKEN":   "raw bytes",
+		"BACKOFF": "1s;2s",
+		"FLAGS":   "1,0,255",
+		"LIMITS":  `{"burst":20}`,
+	})
+
+	s := envconv.NewVarSet("test")
+	level := s.Int8("LEVEL", 0, "log level")
+	port := s.Uint16("PORT", 80, "listen port")
+	ratio := s.Float32("RATIO", 1, "sample ratio")
+	mask := s.Byte("MASK", 0, "permission mask")
+	token := s.ByteSlice("TOKEN", nil, "token bytes")
+	backoff := s.DurationSlice("BACKOFF", ";", nil, "retry delays")
+	flags := s.Uint8Slice("FLAGS", ",", nil, "flag values")
+	limit := envconv.DeclareJSON(s, "LIMITS", limits{Burst: 1}, "rate limits")
+	ready := s.BoolSlice("READY", ",", []bool{true}, "readiness checks")
+	assert.NoError(t, s.ParseFrom(src), "there should be no error")
+
+	assert.Equal(t, int8(-3), *level, "they should be equal")
+	assert.Equal(t, uint16(8443), *port, "they should be equal")
+	assert.Equal(t, float32(0.75), *ratio, "they should be equal")
+	assert.Equal(t, byte(255), *mask, "they should be equal")
+	assert.Equal(t, []byte("raw bytes"), *token, "they should be equal")
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *backoff, "they should be equal")
+	assert.Equal(t, []uint8{1, 0, 255}, *flags, "they should be equal")
+	assert.Equal(t, limits{Burst: 20}, *limit, "they should be equal")
+	assert.Equal(t, []bool{true}, *ready, "they should be equal")
+
+	assert.Equal(t, "[]uint8", s.Lookup("FLAGS").Type, "they should be equal")
+	assert.Equal(t, `{"burst":1}`, s.Lookup("LIMITS").DefValue, "they should be equal")
+	env, err := s.Encode(false)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, `{"burst":20}`, env.Map()["LIMITS"], "they should be equal")
+	assert.Equal(t, "1,0,255", env.Map()["FLAGS"], "they should be equal")
+}
+
+func TestVarSetRedefined(t *testing.T) {
+	s := envconv.NewVarSet("test")
+	s.Int("TEST_VARSET_REDEFINED", 1, "")
+	assert.PanicsWithValue(t, "envconv: variable redefined: TEST_VARSET_REDEFINED", func() {
+		s.String("TEST_VARSET_REDEFINED", "", "")
+	})
+}
+
+func TestVarSetOptions(t *testing.T) {
+	os.Setenv("TEST_VARSET_OPTIONS_OLD", "7")
+
+	s := envconv.NewVarSet("test")
+	workers := s.Int64("TEST_VARSET_OPTIONS", 1, "worker count", envconv.Aliases("TEST_VARSET_OPTIONS_OLD"))
+	assert.NoError(t, s.Parse(), "there should be no error")
+	assert.Equal(t, int64(7), *workers, "they should be equal")
+}
+
+func TestDefaultVarSet(t *testing.T) {
+	os.Setenv("TEST_DEFAULT_VARSET_PORT", "9091")
+
+	port := envconv.Int("TEST_DEFAULT_VARSET_PORT", 8080, "listen port")
+	assert.NoError(t, envconv.Parse(), "there should be no error")
+	assert.True(t, envconv.Parsed(), "it should be parsed")
+	assert.Equal(t, 9091, *port, "they should be equal")
+	assert.Equal(t, "listen port", envconv.Lookup("TEST_DEFAULT_VARSET_PORT").Usage, "they should be equal")
+
+	var names []string
+	envconv.VisitAll(func(v *envconv.Var) {
+		names = append(names, v.Name)
+	})
+	assert.Contains(t, names, "TEST_DEFAULT_VARSET_PORT")
+}
@@ -0,0 +1,197 @@
+package envconv
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ChangeKind describes how a variable differs between two Sources.
+type ChangeKind int
+
+const (
+	// Added means the variable is only set in the new Source.
+	Added ChangeKind = iota
+
+	// Removed means the variable is only set in the old Source.
+	Removed
+
+	// Modified means the variable is set in both Sources, to
+	// different values.
+	Modified
+)
+
+// String returns the name of the change kind, in lower case.
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "unknown"
+}
+
+// Change describes a single variable that differs between two
+// Sources. Old is empty for an added variable, and New is
+// empty for a removed one.
+type Change struct {
+	Name string
+	Kind ChangeKind
+	Old  string
+	New  string
+}
+
+// diff returns the variables that differ between the from and to
+// Sources, in lexicographical order of name.
+func diff(from, to Source) []Change {
+	oldValues, newValues := environMap(from), environMap(to)
+	var changes []Change
+	for name, o := range oldValues {
+		n, ok := newValues[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Name: name, Kind: Removed, Old: o})
+		case n != o:
+			changes = append(changes, Change{Name: name, Kind: Modified, Old: o, New: n})
+		}
+	}
+	for name, n := range newValues {
+		if _, ok := oldValues[name]; !ok {
+			changes = append(changes, Change{Name: name, Kind: Added, New: n})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+// Watcher keeps a FileSource up to date with the dotenv file or
+// directory it was read from, by polling it for changes. Each
+// new version is passed to an apply function, and is only
+// accepted if that function succeeds.
+//
+// As Run calls apply from its own goroutine, apply must be safe to
+// call while the values it sets are read elsewhere. The ReloadFrom
+// method of a Value is, and with ToStruct as the load function of
+// that Value it re-decodes a whole struct, which is only swapped in
+// if it loads and passes validation. VarSet.ParseFrom is too, as
+// long as its variables are read with Var.Get rather than through
+// the pointers returned when they were declared.
+//
+// The values of a Change are never redacted, so subscribers must
+// take care not to log the values of secret variables.
+type Watcher struct {
+	path     string
+	interval time.Duration
+	apply    func(Source) error
+
+	reload      sync.Mutex
+	mu          sync.Mutex
+	source      *FileSource
+	subscribers []func([]Change)
+	onError     func(error)
+}
+
+// NewWatcher reads the dotenv file or directory at path with
+// ReadFileSource and passes it to apply, which may be nil.
+// An error is returned if either step fails. The
+// returned Watcher polls path every interval
+// once Run is called.
+func NewWatcher(path string, interval time.Duration, apply func(Source) error) (*Watcher, error) {
+	w := &Watcher{path: path, interval: interval, apply: apply}
+	src, err := ReadFileSource(path)
+	if err != nil {
+		return nil, err
+	}
+	if apply != nil {
+		if err := apply(src); err != nil {
+			return nil, err
+		}
+	}
+	w.source = src
+	return w, nil
+}
+
+// Source returns the most recently accepted version of the
+// watched file or directory.
+func (w *Watcher) Source() *FileSource {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.source
+}
+
+// Subscribe adds a function to be called with the list of changed
+// variables each time a new version is accepted.
+func (w *Watcher) Subscribe(fn func([]Change)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.subscribers = append(w.subscribers, fn)
+}
+
+// OnError sets a function to be called by Run whenever a new
+// version cannot be read, or is rejected by apply.
+func (w *Watcher) OnError(fn func(error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onError = fn
+}
+
+// Reload reads the watched file or directory again. If any variable
+// has changed, the new version is passed to apply and, if that
+// succeeds, it replaces the current Source and subscribers are
+// notified. Reload returns the changes it accepted. On error
+// the current Source is kept and no changes are returned.
+func (w *Watcher) Reload() ([]Change, error) {
+	w.reload.Lock()
+	defer w.reload.Unlock()
+
+	src, err := ReadFileSource(w.path)
+	if err != nil {
+		return nil, err
+	}
+	changes := diff(w.Source(), src)
+	if changes == nil {
+		return nil, nil
+	}
+	if w.apply != nil {
+		if err := w.apply(src); err != nil {
+			return nil, err
+		}
+	}
+
+	w.mu.Lock()
+	w.source = src
+	subscribers := w.subscribers
+	w.mu.Unlock()
+	for _, fn := range subscribers {
+		fn(changes)
+	}
+	return changes, nil
+}
+
+// Run calls Reload every interval until ctx is done, and then
+// returns the context's error.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := w.Reload(); err != nil {
+				w.mu.Lock()
+				onError := w.onError
+				w.mu.Unlock()
+				if onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package envconv_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rmhubbert/envconv"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatcher(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "TEST_WATCH_RATE=10\nTEST_WATCH_LEVEL=info\n")
+
+	s := envconv.NewVarSet("test")
+	rate := s.Int("TEST_WATCH_RATE", 1, "requests per second")
+	level := s.String("TEST_WATCH_LEVEL", "warn", "log level")
+	w, err := envconv.NewWatcher(path, time.Hour, s.ParseFrom)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, 10, *rate, "they should be equal")
+	assert.Equal(t, "info", *level, "they should be equal")
+
+	var notified [][]envconv.Change
+	w.Subscribe(func(changes []envconv.Change) {
+		notified = append(notified, changes)
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		changes, err := w.Reload()
+		assert.NoError(t, err, "there should be no error")
+		assert.Nil(t, changes, "there should be no changes")
+		assert.Nil(t, notified, "there should be no notifications")
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		writeFile(t, path, "TEST_WATCH_RATE=20\nTEST_WATCH_DEBUG=true\n")
+		expected := []envconv.Change{
+			{Name: "TEST_WATCH_DEBUG", Kind: envconv.Added, New: "true"},
+			{Name: "TEST_WATCH_LEVEL", Kind: envconv.Removed, Old: "info"},
+			{Name: "TEST_WATCH_RATE", Kind: envconv.Modified, Old: "10", New: "20"},
+		}
+
+		changes, err := w.Reload()
+		assert.NoError(t, err, "there should be no error")
+		assert.Equal(t, expected, changes, "they should be equal")
+		assert.Equal(t, [][]envconv.Change{expected}, notified, "they should be equal")
+		assert.Equal(t, 20, *rate, "they should be equal")
+		assert.Equal(t, "warn", *level, "they should be equal")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		notified = nil
+		writeFile(t, path, "TEST_WATCH_RATE=fast\nTEST_WATCH_LEVEL=debug\n")
+
+		changes, err := w.Reload()
+		assert.ErrorContains(t, err, "TEST_WATCH_RATE")
+		assert.Nil(t, changes, "there should be no changes")
+		assert.Nil(t, notified, "there should be no notifications")
+		assert.Equal(t, 20, *rate, "they should be equal")
+		assert.Equal(t, "warn", *level, "they should be equal")
+
+		v, _ := w.Source().Lookup("TEST_WATCH_RATE")
+		assert.Equal(t, "20", v, "they should be equal")
+	})
+}
+
+func TestWatcherRun(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "TEST_WATCH_RUN=1\n")
+	w, err := envconv.NewWatcher(path, time.Millisecond, nil)
+	assert.NoError(t, err, "there should be no error")
+
+	changed := make(chan []envconv.Change, 1)
+	w.Subscribe(func(changes []envconv.Change) {
+		changed <- changes
+	})
+	failed := make(chan error, 1)
+	w.OnError(func(err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+
+	writeFile(t, path, "TEST_WATCH_RUN=2\n")
+	assert.Equal(t, []envconv.Change{{Name: "TEST_WATCH_RUN", Kind: envconv.Modified, Old: "1", New: "2"}}, <-changed, "they should be equal")
+
+	assert.NoError(t, os.Remove(path))
+	assert.ErrorIs(t, <-failed, os.ErrNotExist)
+
+	cancel()
+	assert.True(t, errors.Is(<-done, context.Canceled), "it should be canceled")
+}
+
+func TestNewWatcherRejected(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "TEST_WATCH_REJECTED=fast\n")
+	s := envconv.NewVarSet("test")
+	s.Int("TEST_WATCH_REJECTED", 1, "")
+
+	_, err := envconv.NewWatcher(path, time.Hour, s.ParseFrom)
+	assert.ErrorContains(t, err, "TEST_WATCH_REJECTED")
+}
+
+func TestWatcherStruct(t *testing.T) {
+	type config struct {
+		Rate  int    `env:"RATE" default:"1"`
+		Level string `env:"LEVEL" required:"true"`
+	}
+
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "APP_RATE=10\nAPP_LEVEL=info\n")
+	cfg := envconv.NewValue("APP_", config{}, envconv.ToStruct[config])
+	cfg.Validate(func(c config) error {
+		if c.Rate > 100 {
+			return errors.New("rate is too high")
+		}
+		return nil
+	})
+	w, err := envconv.NewWatcher(path, time.Hour, cfg.ReloadFrom)
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, config{Rate: 10, Level: "info"}, cfg.Load(), "they should be equal")
+
+	writeFile(t, path, "APP_LEVEL=debug\n")
+	_, err = w.Reload()
+	assert.NoError(t, err, "there should be no error")
+	assert.Equal(t, config{Rate: 1, Level: "debug"}, cfg.Load(), "an unset field should take its default")
+
+	writeFile(t, path, "APP_RATE=1000\nAPP_LEVEL=warn\n")
+	_, err = w.Reload()
+	assert.ErrorContains(t, err, "rate is too high")
+	assert.Equal(t, config{Rate: 1, Level: "debug"}, cfg.Load(), "a rejected struct should not be swapped in")
+
+	writeFile(t, path, "APP_RATE=20\n")
+	_, err = w.Reload()
+	assert.ErrorIs(t, err, envconv.ErrNotSet, "the error should wrap ErrNotSet")
+	assert.Equal(t, config{Rate: 1, Level: "debug"}, cfg.Load(), "a missing required field should not fall back to the default")
+}
+
+func TestWatcherConcurrentReads(t *testing.T) {
+	path := writeFile(t, filepath.Join(t.TempDir(), ".env"), "TEST_WATCH_RACE_RATE=0\n")
+
+	var cfg struct {
+		Rate  int      `env:"TEST_WATCH_RACE_RATE"`
+		Hosts []string `env:"TEST_WATCH_RACE_HOSTS" separator:","`
+	}
+	r := envconv.NewReport()
+	s := envconv.NewVarSet("test")
+	assert.NoError(t, s.Struct(&cfg, envconv.Record(r)), "there should be no error")
+	level := s.String("TEST_WATCH_RACE_LEVEL", "info", "", envconv.Record(r))
+
+	w, err := envconv.NewWatcher(path, time.Millisecond, s.ParseFrom)
+	assert.NoError(t, err, "there should be no error")
+	changed := make(chan struct{}, 1)
+	w.Subscribe(func([]envconv.Change) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Run(ctx)
+
+	var wg sync.WaitGroup
+	handler := envconv.Handler(s, r)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				s.Lookup("TEST_WATCH_RACE_RATE").Get()
+				_ = s.Lookup("TEST_WATCH_RACE_HOSTS").String()
+				s.Lookup("TEST_WATCH_RACE_LEVEL").Get()
+				s.Usage()
+				_, _ = s.Encode(true)
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			}
+		}()
+	}
+
+	for i := 1; i <= 5; i++ {
+		writeFile(t, path, fmt.Sprintf("TEST_WATCH_RACE_RATE=%d\nTEST_WATCH_RACE_HOSTS=a,b\nTEST_WATCH_RACE_LEVEL=debug\n", i))
+		select {
+		case <-changed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the change was not applied")
+		}
+		assert.Equal(t, i, s.Lookup("TEST_WATCH_RACE_RATE").Get(), "they should be equal")
+	}
+	cancel()
+	wg.Wait()
+	assert.Equal(t, "debug", *level, "they should be equal")
+	assert.Equal(t, []string{"a", "b"}, cfg.Hosts, "they should be equal")
+}